@@ -147,6 +147,63 @@ const (
 	WebPasswordFlag            = "web-password"
 	WebsocketCheckOrigin       = "websocket-check-origin"
 
+	// Markdown renderer options.
+	MarkdownCodeFenceFlag                     = "markdown-code-fence"
+	MarkdownCollapseComputedAttributesFlag    = "markdown-collapse-computed-attributes"
+	MarkdownCollapseDataSourceReadsFlag       = "markdown-collapse-data-source-reads"
+	MarkdownCollapseDiffContextFlag           = "markdown-collapse-diff-context"
+	MarkdownCollapseProvisionerOutputFlag     = "markdown-collapse-provisioner-output"
+	MarkdownCommentBudgetFlag                 = "markdown-comment-budget"
+	MarkdownDiffContextLinesFlag              = "markdown-diff-context-lines"
+	MarkdownDirectoryTableColumnsFlag         = "markdown-directory-table-columns"
+	MarkdownDirectoryTableThresholdFlag       = "markdown-directory-table-threshold"
+	MarkdownDisableNewlineNormalizationFlag   = "markdown-disable-newline-normalization"
+	MarkdownEmbedChecksumFlag                 = "markdown-embed-checksum"
+	MarkdownExpandLogFlag                     = "markdown-expand-log"
+	MarkdownForceMultiLayoutFlag              = "markdown-force-multi-layout"
+	MarkdownGroupByOwnerFlag                  = "markdown-group-by-owner"
+	MarkdownGroupResourceChangesByModuleFlag  = "markdown-group-resource-changes-by-module"
+	MarkdownIconsFlag                         = "markdown-icons"
+	MarkdownInlineNoChangesFlag               = "markdown-inline-no-changes"
+	MarkdownKnownIssuesFlag                   = "markdown-known-issues"
+	MarkdownLocaleFlag                        = "markdown-locale"
+	MarkdownLogSummaryFlag                    = "markdown-log-summary"
+	MarkdownMaxCommentLengthFlag              = "markdown-max-comment-length"
+	MarkdownMaxFoldDepthFlag                  = "markdown-max-fold-depth"
+	MarkdownMaxModuleDepthFlag                = "markdown-max-module-depth"
+	MarkdownMaxResourceChangesFlag            = "markdown-max-resource-changes"
+	MarkdownMaxWarningsFlag                   = "markdown-max-warnings"
+	MarkdownMinimalFlag                       = "markdown-minimal"
+	MarkdownNoOutputMessageFlag               = "markdown-no-output-message"
+	MarkdownPlanViewFlag                      = "markdown-plan-view"
+	MarkdownPrettyPrintJSONFlag               = "markdown-pretty-print-json"
+	MarkdownPrintFriendlyFlag                 = "markdown-print-friendly"
+	MarkdownProjectPathRootFlag               = "markdown-project-path-root"
+	MarkdownQuietFlag                         = "markdown-quiet"
+	MarkdownReverseSectionOrderFlag           = "markdown-reverse-section-order"
+	MarkdownRunbookURLFlag                    = "markdown-runbook-url"
+	MarkdownSecretMaskPatternsFlag            = "markdown-secret-mask-patterns"
+	MarkdownShortenModuleSourcesFlag          = "markdown-shorten-module-sources"
+	MarkdownShowApplySnippetFlag              = "markdown-show-apply-snippet"
+	MarkdownShowDebugInfoFlag                 = "markdown-show-debug-info"
+	MarkdownShowGeneratedAtFlag               = "markdown-show-generated-at"
+	MarkdownShowNextStepsFlag                 = "markdown-show-next-steps"
+	MarkdownShowPlanDiffLineCountFlag         = "markdown-show-plan-diff-line-count"
+	MarkdownShowProjectAnchorsFlag            = "markdown-show-project-anchors"
+	MarkdownShowResourceChangesFlag           = "markdown-show-resource-changes"
+	MarkdownShowResourceStatsFlag             = "markdown-show-resource-stats"
+	MarkdownShowStatusTableFlag               = "markdown-show-status-table"
+	MarkdownShowWelcomeFlag                   = "markdown-show-welcome"
+	MarkdownSortResourceChangesBySeverityFlag = "markdown-sort-resource-changes-by-severity"
+	MarkdownStalePlanAgeFlag                  = "markdown-stale-plan-age"
+	MarkdownStatusEmojiFlag                   = "markdown-status-emoji"
+	MarkdownSuccessMessagesFlag               = "markdown-success-messages"
+	MarkdownThreadKeyFlag                     = "markdown-thread-key"
+	MarkdownTicketLinksFlag                   = "markdown-ticket-links"
+	MarkdownTimeZoneFlag                      = "markdown-time-zone"
+	MarkdownWelcomeMessageFlag                = "markdown-welcome-message"
+	MarkdownWrapInRegionMarkersFlag           = "markdown-wrap-in-region-markers"
+
 	// NOTE: Must manually set these as defaults in the setDefaults function.
 	DefaultADBasicUser                  = ""
 	DefaultADBasicPassword              = ""
@@ -340,6 +397,57 @@ var stringFlags = map[string]stringFlag{
 		description:  "Log level. Either debug, info, warn, or error.",
 		defaultValue: DefaultLogLevel,
 	},
+	MarkdownCodeFenceFlag: {
+		description: "String that opens and closes code blocks in comments, ex. \"~~~\". Defaults to \"```\".",
+	},
+	MarkdownCommentBudgetFlag: {
+		description: "JSON object with the fractions of --markdown-max-comment-length given to the output (\"diff\") and log (\"log\") of comments, ex. {\"diff\": 0.6, \"log\": 0.2}. Each section is truncated to its share.",
+	},
+	MarkdownIconsFlag: {
+		description: "JSON object overriding the icons of statuses. Keys are \"success\", \"error\", \"failure\", \"warning\", a failure category, or \"label:\" followed by a project label.",
+	},
+	MarkdownKnownIssuesFlag: {
+		description: "JSON array of objects with a \"pattern\" regular expression for errors and the \"url\" of an article about them. Errors link to the first match.",
+	},
+	MarkdownLocaleFlag: {
+		description: "Language of the comments, ex. of the templates in --markdown-template-overrides-dir. Right-to-left languages, ex. \"ar\" or \"he\", render their prose right to left.",
+	},
+	MarkdownLogSummaryFlag: {
+		description: "Title of the collapsed log section of verbose comments. Defaults to \"Log\".",
+	},
+	MarkdownNoOutputMessageFlag: {
+		description: "Message rendered when there is no output to render for a project.",
+	},
+	MarkdownPlanViewFlag: {
+		description: "View of each plan shown expanded, either \"parsed\" or \"raw\", with the other view collapsed below it. If not set only the raw plan is shown.",
+	},
+	MarkdownProjectPathRootFlag: {
+		description: "Directory trimmed from the start of the project directories under it in comments.",
+	},
+	MarkdownRunbookURLFlag: {
+		description: "URL of a runbook linked in the header of comments.",
+	},
+	MarkdownSecretMaskPatternsFlag: {
+		description: "JSON array of regular expressions. Matches in plan and apply output are replaced with \"***\" in comments.",
+	},
+	MarkdownStalePlanAgeFlag: {
+		description: "Age of plans beyond which applies warn that they used an old plan, ex. \"2h\". Defaults to 1h.",
+	},
+	MarkdownSuccessMessagesFlag: {
+		description: "JSON object mapping commands, ex. \"plan\", to a message rendered above the output of each project the command succeeded for.",
+	},
+	MarkdownThreadKeyFlag: {
+		description: "Line starting comments, ex. an HTML comment that the VCS host groups comments by.",
+	},
+	MarkdownTicketLinksFlag: {
+		description: "JSON array of objects with a \"pattern\" regular expression for ticket references, ex. \"JIRA-\\\\d+\", and the \"url\" they link to, ex. \"https://jira.example.com/browse/$0\".",
+	},
+	MarkdownTimeZoneFlag: {
+		description: "Time zone of the timestamps in comments, ex. \"Europe/Berlin\". Defaults to UTC.",
+	},
+	MarkdownWelcomeMessageFlag: {
+		description: "Message greeting first-time contributors when --markdown-show-welcome is enabled.",
+	},
 	MarkdownTemplateOverridesDirFlag: {
 		description:  "Directory for custom overrides to the markdown templates used for comments.",
 		defaultValue: DefaultMarkdownTemplateOverridesDir,
@@ -570,6 +678,126 @@ var boolFlags = map[string]boolFlag{
 		description:  "Enable websocket origin check",
 		defaultValue: false,
 	},
+	MarkdownCollapseComputedAttributesFlag: {
+		description:  "Replace runs of attributes that are \"(known after apply)\" in plans with a count of them.",
+		defaultValue: false,
+	},
+	MarkdownCollapseDataSourceReadsFlag: {
+		description:  "Replace the lines plans print while reading data sources with a count of them.",
+		defaultValue: false,
+	},
+	MarkdownCollapseDiffContextFlag: {
+		description:  "Show only the changed lines of plans, plus --markdown-diff-context-lines lines around them, with the full plan in a collapsed section.",
+		defaultValue: false,
+	},
+	MarkdownCollapseProvisionerOutputFlag: {
+		description:  "Collapse the output of local-exec and remote-exec provisioners in apply output.",
+		defaultValue: false,
+	},
+	MarkdownDisableNewlineNormalizationFlag: {
+		description:  "Keep CRLF and CR line endings in output instead of converting them to LF.",
+		defaultValue: false,
+	},
+	MarkdownEmbedChecksumFlag: {
+		description:  "End comments with a hidden checksum of their content.",
+		defaultValue: false,
+	},
+	MarkdownExpandLogFlag: {
+		description:  "Render the log of verbose comments below a separator instead of in a collapsed section.",
+		defaultValue: false,
+	},
+	MarkdownForceMultiLayoutFlag: {
+		description:  "Render comments with the multi-project layout even if there's only one project.",
+		defaultValue: false,
+	},
+	MarkdownGroupByOwnerFlag: {
+		description:  "Group the sections of multi-project comments under a heading for the owner of their projects.",
+		defaultValue: false,
+	},
+	MarkdownGroupResourceChangesByModuleFlag: {
+		description:  "Group the resources listed by --markdown-show-resource-changes under the module that contains them.",
+		defaultValue: false,
+	},
+	MarkdownInlineNoChangesFlag: {
+		description:  "Annotate plans without changes in the project list of multi-project plan comments instead of rendering their sections.",
+		defaultValue: false,
+	},
+	MarkdownMinimalFlag: {
+		description:  "Render only the command, its status and a one-line summary per project in comments.",
+		defaultValue: false,
+	},
+	MarkdownPrettyPrintJSONFlag: {
+		description:  "Move long JSON strings in plans, ex. minified policy documents, into collapsed, pretty-printed blocks.",
+		defaultValue: false,
+	},
+	MarkdownPrintFriendlyFlag: {
+		description:  "Render comments for print: expand collapsed sections and remove instructions, links and checkboxes.",
+		defaultValue: false,
+	},
+	MarkdownQuietFlag: {
+		description:  "Don't comment plans that succeeded without changes in every project.",
+		defaultValue: false,
+	},
+	MarkdownReverseSectionOrderFlag: {
+		description:  "Render the project sections of multi-project plan comments newest-first.",
+		defaultValue: false,
+	},
+	MarkdownShortenModuleSourcesFlag: {
+		description:  "Shorten git module sources in plans, ex. to \"github.com/org/modules//vpc@v1.0.0\".",
+		defaultValue: false,
+	},
+	MarkdownShowApplySnippetFlag: {
+		description:  "Render the comment that applies each plan in a code block so it can be copied.",
+		defaultValue: false,
+	},
+	MarkdownShowDebugInfoFlag: {
+		description:  "End comments with a collapsed section listing the Terraform version, workspace and directory of each project.",
+		defaultValue: false,
+	},
+	MarkdownShowGeneratedAtFlag: {
+		description:  "End comments with the time they were generated.",
+		defaultValue: false,
+	},
+	MarkdownShowNextStepsFlag: {
+		description:  "End comments with a line suggesting what to do next.",
+		defaultValue: false,
+	},
+	MarkdownShowPlanDiffLineCountFlag: {
+		description:  "Show the number of lines of each project's plan in the section headers of multi-project plan comments.",
+		defaultValue: false,
+	},
+	MarkdownShowProjectAnchorsFlag: {
+		description:  "Render an HTML anchor before each project section of comments so that it can be linked to.",
+		defaultValue: false,
+	},
+	MarkdownShowResourceChangesFlag: {
+		description:  "List the resources each plan changes below the plan output.",
+		defaultValue: false,
+	},
+	MarkdownShowResourceStatsFlag: {
+		description:  "Show the number of attribute lines added and removed next to each resource listed by --markdown-show-resource-changes.",
+		defaultValue: false,
+	},
+	MarkdownShowStatusTableFlag: {
+		description:  "Render a table with the status and changes of each project before the sections of multi-project comments.",
+		defaultValue: false,
+	},
+	MarkdownShowWelcomeFlag: {
+		description:  "Start comments for first-time contributors with --markdown-welcome-message.",
+		defaultValue: false,
+	},
+	MarkdownSortResourceChangesBySeverityFlag: {
+		description:  "List the resources listed by --markdown-show-resource-changes by the severity of their action, destroys first.",
+		defaultValue: false,
+	},
+	MarkdownStatusEmojiFlag: {
+		description:  "Prefix the statuses in the table enabled by --markdown-show-status-table with emoji.",
+		defaultValue: false,
+	},
+	MarkdownWrapInRegionMarkersFlag: {
+		description:  "Wrap comments in HTML comments marking their start and end.",
+		defaultValue: false,
+	},
 	HideUnchangedPlanComments: {
 		description:  "Remove no-changes plan comments from the pull request.",
 		defaultValue: false,
@@ -586,6 +814,30 @@ var intFlags = map[string]intFlag{
 			" If merge base is further behind than this number of commits from any of branches heads, full fetch will be performed.",
 		defaultValue: DefaultCheckoutDepth,
 	},
+	MarkdownDiffContextLinesFlag: {
+		description: "Number of unchanged lines kept around each changed line when --markdown-collapse-diff-context is enabled.",
+	},
+	MarkdownDirectoryTableColumnsFlag: {
+		description: "Number of columns of the table enabled by --markdown-directory-table-threshold. Defaults to 2.",
+	},
+	MarkdownDirectoryTableThresholdFlag: {
+		description: "Number of projects above which multi-project comments list the projects in a table linking to their sections. 0 disables the table.",
+	},
+	MarkdownMaxCommentLengthFlag: {
+		description: "Maximum length of comments. Longer comments are truncated. 0 means no limit.",
+	},
+	MarkdownMaxFoldDepthFlag: {
+		description: "Maximum number of nested collapsible sections in comments. Deeper sections are rendered expanded. 0 means unlimited.",
+	},
+	MarkdownMaxModuleDepthFlag: {
+		description: "Deepest module nesting that gets its own group when --markdown-group-resource-changes-by-module is enabled. 0 means unlimited.",
+	},
+	MarkdownMaxResourceChangesFlag: {
+		description: "Number of changed resources listed by --markdown-show-resource-changes before the rest are collapsed. Defaults to 25.",
+	},
+	MarkdownMaxWarningsFlag: {
+		description: "Number of deprecations and authentication notices listed for each plan before the rest are collapsed. Defaults to 10.",
+	},
 	ParallelPoolSize: {
 		description:  "Max size of the wait group that runs parallel plans and applies (if enabled).",
 		defaultValue: DefaultParallelPoolSize,
@@ -980,6 +1232,10 @@ func (s *ServerCmd) validate(userConfig server.UserConfig) error {
 		return errors.Wrapf(err, "invalid --%s", AllowCommandsFlag)
 	}
 
+	if _, err := userConfig.ToMarkdownRendererOptions(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
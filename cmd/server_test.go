@@ -119,6 +119,63 @@ var testFlags = map[string]interface{}{
 	EnablePolicyChecksFlag:           false,
 	EnableRegExpCmdFlag:              false,
 	EnableDiffMarkdownFormat:         false,
+
+	// Markdown renderer options.
+	MarkdownCodeFenceFlag:                     "code-fence",
+	MarkdownCollapseComputedAttributesFlag:    true,
+	MarkdownCollapseDataSourceReadsFlag:       true,
+	MarkdownCollapseDiffContextFlag:           true,
+	MarkdownCollapseProvisionerOutputFlag:     true,
+	MarkdownCommentBudgetFlag:                 `{"diff": 0.6, "log": 0.2}`,
+	MarkdownDiffContextLinesFlag:              3,
+	MarkdownDirectoryTableColumnsFlag:         4,
+	MarkdownDirectoryTableThresholdFlag:       5,
+	MarkdownDisableNewlineNormalizationFlag:   true,
+	MarkdownEmbedChecksumFlag:                 true,
+	MarkdownExpandLogFlag:                     true,
+	MarkdownForceMultiLayoutFlag:              true,
+	MarkdownGroupByOwnerFlag:                  true,
+	MarkdownGroupResourceChangesByModuleFlag:  true,
+	MarkdownIconsFlag:                         `{"success": ":white_check_mark:"}`,
+	MarkdownInlineNoChangesFlag:               true,
+	MarkdownKnownIssuesFlag:                   `[{"pattern": "JIRA-\\d+", "url": "https://jira.example.com/browse/$0"}]`,
+	MarkdownLocaleFlag:                        "locale",
+	MarkdownLogSummaryFlag:                    "log-summary",
+	MarkdownMaxCommentLengthFlag:              6,
+	MarkdownMaxFoldDepthFlag:                  7,
+	MarkdownMaxModuleDepthFlag:                8,
+	MarkdownMaxResourceChangesFlag:            9,
+	MarkdownMaxWarningsFlag:                   10,
+	MarkdownMinimalFlag:                       true,
+	MarkdownNoOutputMessageFlag:               "no-output-message",
+	MarkdownPlanViewFlag:                      "parsed",
+	MarkdownPrettyPrintJSONFlag:               true,
+	MarkdownPrintFriendlyFlag:                 true,
+	MarkdownProjectPathRootFlag:               "project-path-root",
+	MarkdownQuietFlag:                         true,
+	MarkdownReverseSectionOrderFlag:           true,
+	MarkdownRunbookURLFlag:                    "runbook-url",
+	MarkdownSecretMaskPatternsFlag:            `["password=\\S+"]`,
+	MarkdownShortenModuleSourcesFlag:          true,
+	MarkdownShowApplySnippetFlag:              true,
+	MarkdownShowDebugInfoFlag:                 true,
+	MarkdownShowGeneratedAtFlag:               true,
+	MarkdownShowNextStepsFlag:                 true,
+	MarkdownShowPlanDiffLineCountFlag:         true,
+	MarkdownShowProjectAnchorsFlag:            true,
+	MarkdownShowResourceChangesFlag:           true,
+	MarkdownShowResourceStatsFlag:             true,
+	MarkdownShowStatusTableFlag:               true,
+	MarkdownShowWelcomeFlag:                   true,
+	MarkdownSortResourceChangesBySeverityFlag: true,
+	MarkdownStalePlanAgeFlag:                  "2h",
+	MarkdownStatusEmojiFlag:                   true,
+	MarkdownSuccessMessagesFlag:               `{"plan": "Plan succeeded."}`,
+	MarkdownThreadKeyFlag:                     "thread-key",
+	MarkdownTicketLinksFlag:                   `[{"pattern": "JIRA-\\d+", "url": "https://jira.example.com/browse/$0"}]`,
+	MarkdownTimeZoneFlag:                      "UTC",
+	MarkdownWelcomeMessageFlag:                "welcome-message",
+	MarkdownWrapInRegionMarkersFlag:           true,
 }
 
 func TestExecute_Defaults(t *testing.T) {
@@ -318,6 +375,14 @@ func TestExecute_ValidateCheckoutStrategy(t *testing.T) {
 	ErrEquals(t, "invalid checkout strategy: not one of branch or merge", err)
 }
 
+func TestExecute_ValidateMarkdownRendererOptions(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		MarkdownPlanViewFlag: "invalid",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, `invalid --markdown-plan-view "invalid": must be "parsed" or "raw"`, err)
+}
+
 func TestExecute_ValidateSSLConfig(t *testing.T) {
 	expErr := "--ssl-key-file and --ssl-cert-file are both required for ssl"
 	cases := []struct {
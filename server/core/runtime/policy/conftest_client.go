@@ -208,10 +208,11 @@ func (c *ConfTestExecutorWorkflow) Run(ctx command.ProjectContext, executablePat
 		}
 
 		policySetResults = append(policySetResults, models.PolicySetResult{
-			PolicySetName:  policySet.Name,
-			ConftestOutput: cmdOutput,
-			Passed:         passed,
-			ReqApprovals:   policySet.ApproveCount,
+			PolicySetName:   policySet.Name,
+			ConftestOutput:  cmdOutput,
+			Passed:          passed,
+			ReqApprovals:    policySet.ApproveCount,
+			PolicySetSource: policySet.Path,
 		})
 	}
 
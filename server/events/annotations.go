@@ -0,0 +1,92 @@
+package events
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Annotation locates an error in a file of the repo, ex. for an inline
+// annotation on a pull request. Line is 0 for errors that aren't about a
+// specific line.
+type Annotation struct {
+	Path    string
+	Line    int
+	Message string
+}
+
+var (
+	// reDiagnosticStart matches the summary line that starts each error
+	// Terraform reports, optionally prefixed by the box-drawing characters
+	// it uses for diagnostics.
+	reDiagnosticStart = regexp.MustCompile(`^[\s│╷]*Error: (.*?)\s*$`)
+	// reDiagnosticEnd matches the line that ends a boxed diagnostic.
+	reDiagnosticEnd = regexp.MustCompile(`^\s*╵\s*$`)
+	// reDiagnosticLocation matches the source location of a diagnostic, ex.
+	// "on main.tf line 12, in resource ...".
+	reDiagnosticLocation = regexp.MustCompile(`^\s*on (\S+) line (\d+)`)
+	// reDiagnosticSource matches the lines of a diagnostic that quote the
+	// source around its location, ex. "12:   foo = bar".
+	reDiagnosticSource = regexp.MustCompile(`^\s*(\d+:|├|│|\|)`)
+)
+
+// ParseAnnotations parses the errors in the output of a Terraform command run
+// in dir, which is relative to the root of the repo. Errors with a source
+// location are annotated on that line, while errors without one, or output
+// without any recognizable error, are annotated on dir itself.
+func ParseAnnotations(dir string, output string) []Annotation {
+	var annotations []Annotation
+	var current *Annotation
+	var detail []string
+	inLocation := false
+	finish := func() {
+		if current == nil {
+			return
+		}
+		if d := strings.TrimSpace(strings.Join(detail, "\n")); d != "" {
+			current.Message += "\n\n" + d
+		}
+		annotations = append(annotations, *current)
+		current = nil
+		detail = nil
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		if match := reDiagnosticStart.FindStringSubmatch(line); match != nil {
+			finish()
+			current = &Annotation{Path: dir, Message: match[1]}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if reDiagnosticEnd.MatchString(line) {
+			finish()
+			continue
+		}
+		line = strings.TrimPrefix(strings.TrimLeft(line, " \t"), "│")
+		if match := reDiagnosticLocation.FindStringSubmatch(line); match != nil && current.Line == 0 {
+			current.Path = path.Join(dir, match[1])
+			current.Line, _ = strconv.Atoi(match[2])
+			inLocation = true
+			continue
+		}
+		if inLocation {
+			// The location is followed by the source it refers to, up to
+			// the next blank line.
+			if strings.TrimSpace(line) == "" {
+				inLocation = false
+			} else if reDiagnosticSource.MatchString(line) || strings.HasPrefix(strings.TrimSpace(line), "in ") {
+				continue
+			}
+		}
+		detail = append(detail, strings.TrimSpace(line))
+	}
+	finish()
+
+	if len(annotations) == 0 && strings.TrimSpace(output) != "" {
+		annotations = append(annotations, Annotation{Path: dir, Message: strings.TrimSpace(output)})
+	}
+	return annotations
+}
@@ -0,0 +1,91 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestParseAnnotations(t *testing.T) {
+	cases := []struct {
+		Description string
+		Output      string
+		Expected    []events.Annotation
+	}{
+		{
+			"boxed error with location",
+			`Initializing the backend...
+╷
+│ Error: Unsupported argument
+│
+│   on main.tf line 12, in resource "aws_instance" "web":
+│   12:   foo = "bar"
+│
+│ An argument named "foo" is not expected here.
+╵
+`,
+			[]events.Annotation{{
+				Path:    "path/main.tf",
+				Line:    12,
+				Message: "Unsupported argument\n\nAn argument named \"foo\" is not expected here.",
+			}},
+		},
+		{
+			"multiple errors",
+			`Error: Reference to undeclared input variable
+
+  on modules/vpc/main.tf line 3, in resource "aws_vpc" "main":
+   3:   cidr_block = var.cidr
+
+An input variable with the name "cidr" has not been declared.
+
+Error: Missing required argument
+
+  on main.tf line 7:
+   7: module "vpc" {
+
+The argument "name" is required, but no definition was found.
+`,
+			[]events.Annotation{
+				{
+					Path:    "path/modules/vpc/main.tf",
+					Line:    3,
+					Message: "Reference to undeclared input variable\n\nAn input variable with the name \"cidr\" has not been declared.",
+				},
+				{
+					Path:    "path/main.tf",
+					Line:    7,
+					Message: "Missing required argument\n\nThe argument \"name\" is required, but no definition was found.",
+				},
+			},
+		},
+		{
+			"error without location",
+			"╷\n│ Error: No valid credential sources found\n│\n│ Please see the provider docs.\n╵\n",
+			[]events.Annotation{{
+				Path:    "path",
+				Message: "No valid credential sources found\n\nPlease see the provider docs.",
+			}},
+		},
+		{
+			"unrecognized error",
+			"exit status 1\n",
+			[]events.Annotation{{
+				Path:    "path",
+				Message: "exit status 1",
+			}},
+		},
+		{
+			"no output",
+			"",
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Description, func(t *testing.T) {
+			Equals(t, c.Expected, events.ParseAnnotations("path", c.Output))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// AsciiDocRenderer renders responses as AsciiDoc, ex. to publish summaries of
+// changes in AsciiDoc-based docs.
+type AsciiDocRenderer struct {
+	executableName string
+	templates      *template.Template
+	// disableApply leaves out the comment to apply plans.
+	disableApply bool
+	// secretMaskPatterns are matched against the outputs and every match is
+	// replaced with "***" before the output is rendered.
+	secretMaskPatterns []*regexp.Regexp
+}
+
+// NewAsciiDocRenderer returns a renderer whose output refers to the Atlantis
+// binary as executableName.
+func NewAsciiDocRenderer(executableName string, disableApply bool, secretMaskPatterns []*regexp.Regexp) *AsciiDocRenderer {
+	funcs := sprig.TxtFuncMap()
+	funcs["sourceBlock"] = asciidocSourceBlock
+	funcs["literal"] = asciidocLiteral
+	funcs["projectTitle"] = asciidocProjectTitle
+	funcs["collapsible"] = asciidocShouldCollapse
+	return &AsciiDocRenderer{
+		executableName:     executableName,
+		disableApply:       disableApply,
+		secretMaskPatterns: secretMaskPatterns,
+		templates:          template.Must(template.New("").Funcs(funcs).ParseFS(templatesFS, "templates/asciidoc/*.tmpl")),
+	}
+}
+
+// Render formats the data into an AsciiDoc string. Outputs longer than
+// maxUnwrappedLines are put in collapsible blocks.
+func (r *AsciiDocRenderer) Render(res command.Result, cmdName command.Name, subCmd string) string {
+	common := commonData{
+		Command:        cmdName.TitleString(),
+		SubCommand:     subCmd,
+		ExecutableName: r.executableName,
+		PlansDeleted:   res.PlansDeleted,
+		DisableApply:   r.disableApply,
+	}
+	if res.Error != nil {
+		return r.render("error", errData{Error: strings.TrimSpace(res.Error.Error()), commonData: common})
+	}
+	if res.Failure != "" {
+		return r.render("failure", failureData{Failure: res.Failure, commonData: common})
+	}
+
+	var results []projectResultTmplData
+	for i, result := range res.ProjectResults {
+		resultData := projectResultTmplData{
+			Workspace:   result.Workspace,
+			RepoRelDir:  result.RepoRelDir,
+			ProjectName: result.ProjectName,
+			Num:         i + 1,
+		}
+		name, data := resultTemplate(result, common, r.secretMaskPatterns)
+		if plan, ok := data.(planSuccessData); ok && asciidocShouldCollapse(plan.TerraformOutput) {
+			plan.PlanSummary = plan.PlanSuccess.Summary()
+			data = plan
+		}
+		if name != "" {
+			resultData.Rendered = r.render(name, data)
+		}
+		resultData.NoChanges = result.PlanSuccess != nil && result.PlanSuccess.NoChanges()
+		results = append(results, resultData)
+	}
+	return r.render("results", resultData{Results: results, commonData: common})
+}
+
+func (r *AsciiDocRenderer) render(name string, data interface{}) string {
+	buf := &bytes.Buffer{}
+	if err := r.templates.ExecuteTemplate(buf, name, data); err != nil {
+		return fmt.Sprintf("Failed to render template, this is a bug: %v", err)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// asciidocShouldCollapse returns true if output is long enough to be put in
+// a collapsible block.
+func asciidocShouldCollapse(output string) bool {
+	return strings.Count(output, "\n") > maxUnwrappedLines
+}
+
+// asciidocSourceBlock returns a listing block highlighting code as language.
+// The delimiter is made longer than any line of code that would close it.
+func asciidocSourceBlock(language string, code string) string {
+	delimiter := "----"
+	for _, line := range strings.Split(code, "\n") {
+		if strings.Trim(line, "-") == "" && len(line) >= len(delimiter) {
+			delimiter = line + "-"
+		}
+	}
+	return fmt.Sprintf("[source,%s]\n%s\n%s\n%s", language, delimiter, code, delimiter)
+}
+
+// asciidocLiteral returns s as inline literal monospace text.
+func asciidocLiteral(s string) string {
+	return "`+" + s + "+`"
+}
+
+// asciidocProjectTitle returns the title of the section for result.
+func asciidocProjectTitle(result projectResultTmplData) string {
+	title := fmt.Sprintf("%d. ", result.Num)
+	if result.ProjectName != "" {
+		title += "project: " + asciidocLiteral(result.ProjectName) + " "
+	}
+	return title + "dir: " + asciidocLiteral(result.RepoRelDir) + " workspace: " + asciidocLiteral(result.Workspace)
+}
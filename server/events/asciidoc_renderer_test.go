@@ -0,0 +1,181 @@
+package events_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestAsciiDocRenderer_Render(t *testing.T) {
+	longOutput := strings.Repeat("  + line\n", 13) + "\nPlan: 13 to add, 0 to change, 0 to destroy."
+
+	cases := []struct {
+		Description string
+		Result      command.Result
+		Expected    string
+	}{
+		{
+			"plan",
+			command.Result{ProjectResults: []command.ProjectResult{
+				{
+					Workspace:  "default",
+					RepoRelDir: "path",
+					PlanSuccess: &models.PlanSuccess{
+						TerraformOutput: "  + resource \"null_resource\" \"a\" {\n      + id = (known after apply)\n    }\n\nPlan: 1 to add, 0 to change, 0 to destroy.\n",
+						LockURL:         "lock-url",
+						RePlanCmd:       "atlantis plan -d path",
+						ApplyCmd:        "atlantis apply -d path",
+					},
+				},
+				{
+					Workspace:   "staging",
+					RepoRelDir:  "path2",
+					ProjectName: "projectname",
+					Error:       errors.New("error"),
+				},
+			}},
+			`= Plan Results
+
+== 1. dir: $+path+$ workspace: $+default+$
+
+[source,diff]
+----
+  + resource "null_resource" "a" {
+      + id = (known after apply)
+    }
+
+Plan: 1 to add, 0 to change, 0 to destroy.
+----
+
+* To *apply* this plan, comment: $+atlantis apply -d path+$
+* To *plan* this project again, comment: $+atlantis plan -d path+$
+
+== 2. project: $+projectname+$ dir: $+path2+$ workspace: $+staging+$
+
+*Plan Error*
+
+[source,text]
+----
+error
+----`,
+		},
+		{
+			"long plan",
+			command.Result{ProjectResults: []command.ProjectResult{
+				{
+					Workspace:  "default",
+					RepoRelDir: "path",
+					PlanSuccess: &models.PlanSuccess{
+						TerraformOutput: longOutput,
+						LockURL:         "lock-url",
+						RePlanCmd:       "atlantis plan -d path",
+						ApplyCmd:        "atlantis apply -d path",
+					},
+				},
+			}},
+			`= Plan Results
+
+== 1. dir: $+path+$ workspace: $+default+$
+
+[%collapsible]
+.Show Output
+====
+[source,diff]
+----
+` + longOutput + `
+----
+====
+
+Plan: 13 to add, 0 to change, 0 to destroy.
+
+* To *apply* this plan, comment: $+atlantis apply -d path+$
+* To *plan* this project again, comment: $+atlantis plan -d path+$`,
+		},
+		{
+			"output containing the delimiter",
+			command.Result{ProjectResults: []command.ProjectResult{
+				{
+					Workspace:    "default",
+					RepoRelDir:   "path",
+					ApplySuccess: "before\n----\nafter",
+				},
+			}},
+			`= Plan Results
+
+== 1. dir: $+path+$ workspace: $+default+$
+
+[source,text]
+-----
+before
+----
+after
+-----`,
+		},
+		{
+			"no projects",
+			command.Result{},
+			"= Plan Results\n\nNo projects matched this command.",
+		},
+		{
+			"error",
+			command.Result{Error: errors.New("error")},
+			"*Plan Error*\n\n[source,text]\n----\nerror\n----",
+		},
+		{
+			"failure",
+			command.Result{Failure: "failure"},
+			"*Plan Failed*: failure",
+		},
+	}
+
+	r := events.NewAsciiDocRenderer("atlantis", false, nil)
+	for _, c := range cases {
+		t.Run(c.Description, func(t *testing.T) {
+			Equals(t, strings.Replace(c.Expected, "$", "`", -1), r.Render(c.Result, command.Plan, ""))
+		})
+	}
+}
+
+func TestAsciiDocRenderer_RenderMasksSecretsWithoutApply(t *testing.T) {
+	r := events.NewAsciiDocRenderer("atlantis", true, []*regexp.Regexp{regexp.MustCompile(`hunter\d`)})
+	res := command.Result{ProjectResults: []command.ProjectResult{
+		{
+			Workspace:  "default",
+			RepoRelDir: "path",
+			PlanSuccess: &models.PlanSuccess{
+				TerraformOutput: "  ~ password = \"hunter1\" -> \"hunter2\"",
+				RePlanCmd:       "atlantis plan -d path",
+				ApplyCmd:        "atlantis apply -d path",
+			},
+		},
+		{
+			Workspace:    "default",
+			RepoRelDir:   "path2",
+			ApplySuccess: "password = hunter3",
+		},
+	}}
+
+	Equals(t, strings.Replace(`= Plan Results
+
+== 1. dir: $+path+$ workspace: $+default+$
+
+[source,diff]
+----
+  ~ password = "***" -> "***"
+----
+
+* To *plan* this project again, comment: $+atlantis plan -d path+$
+
+== 2. dir: $+path2+$ workspace: $+default+$
+
+[source,text]
+----
+password = ***
+----`, "$", "`", -1), r.Render(res, command.Plan, ""))
+}
@@ -0,0 +1,15 @@
+package command
+
+// CancellationReason is why a command was cancelled before it completed.
+type CancellationReason string
+
+const (
+	// NotCancelled means the command wasn't cancelled.
+	NotCancelled CancellationReason = ""
+	// TimeoutCancellation means the command ran longer than it was allowed
+	// to.
+	TimeoutCancellation CancellationReason = "timeout"
+	// SupersededCancellation means a newer run of the command for the same
+	// pull request replaced it.
+	SupersededCancellation CancellationReason = "superseded"
+)
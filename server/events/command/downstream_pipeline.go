@@ -0,0 +1,12 @@
+package command
+
+// DownstreamPipeline is a pipeline, ex. a deployment, that an apply triggered.
+type DownstreamPipeline struct {
+	// Name is the name of the pipeline. It defaults to "pipeline" if empty.
+	Name string
+	// URL links to the pipeline's run.
+	URL string
+	// Status is the status of the run when the comment is rendered, ex.
+	// "running", if known.
+	Status string
+}
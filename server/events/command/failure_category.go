@@ -0,0 +1,22 @@
+package command
+
+// FailureCategory classifies a failure so that it can be rendered with
+// guidance that matches its cause.
+type FailureCategory string
+
+const (
+	// UnknownFailure is a failure that hasn't been categorized.
+	UnknownFailure FailureCategory = ""
+	// UserFailure is a failure caused by the pull request, ex. an invalid
+	// configuration, that the user can fix.
+	UserFailure FailureCategory = "user"
+	// SystemFailure is a failure caused by Atlantis or the infrastructure it
+	// runs on, that the user can't fix.
+	SystemFailure FailureCategory = "system"
+	// PrerequisitesFailure is a failure because checks the command requires,
+	// ex. required status checks, haven't passed yet.
+	PrerequisitesFailure FailureCategory = "prerequisites"
+	// LockedFailure is a failure because the project is locked, ex. by
+	// another pull request, which goes away once the lock is released.
+	LockedFailure FailureCategory = "locked"
+)
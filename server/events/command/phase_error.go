@@ -0,0 +1,17 @@
+package command
+
+// PhaseError is an error from a phase, ex. init or plan, of running a
+// command for a project.
+type PhaseError struct {
+	// Phase is the name of the step that failed, ex. "init".
+	Phase string
+	Err   error
+}
+
+func (p PhaseError) Error() string {
+	return p.Err.Error()
+}
+
+func (p PhaseError) Unwrap() error {
+	return p.Err
+}
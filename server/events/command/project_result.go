@@ -1,6 +1,8 @@
 package command
 
 import (
+	"time"
+
 	"github.com/runatlantis/atlantis/server/events/models"
 )
 
@@ -19,6 +21,39 @@ type ProjectResult struct {
 	ImportSuccess      *models.ImportSuccess
 	StateRmSuccess     *models.StateRmSuccess
 	ProjectName        string
+	// FailureCategory classifies Failure, if set.
+	FailureCategory FailureCategory
+	// ResourceCounts are the number of resources managed by the project
+	// before and after an apply, if known.
+	ResourceCounts *ResourceCounts
+	// UnmetPrerequisites are the checks that haven't passed if
+	// FailureCategory is PrerequisitesFailure.
+	UnmetPrerequisites []string
+	// CancellationReason is why the command was cancelled for the project,
+	// if it was.
+	CancellationReason CancellationReason
+	// Labels are the labels the project is annotated with, ex.
+	// "team:platform".
+	Labels []string
+	// ManualSteps documents steps that must be done by hand before or after
+	// applying the project, if any.
+	ManualSteps string
+	// FmtCheckResult is the result of checking the formatting of the
+	// project's files, if it was checked.
+	FmtCheckResult *models.FmtCheckResult
+	// PlanAge is how long before this command the plan it used was
+	// generated, or 0 if unknown.
+	PlanAge time.Duration
+	// Owner is the team that owns the project, ex. from CODEOWNERS. It is
+	// empty if unassigned.
+	Owner string
+	// TerraformVersion is the version of Terraform the command ran with, if
+	// known.
+	TerraformVersion string
+	// WorkingDir is the directory the command ran in, if known.
+	WorkingDir string
+	// Downstream is the pipeline the apply triggered, if any.
+	Downstream *DownstreamPipeline
 }
 
 // CommitStatus returns the vcs commit status of this project result.
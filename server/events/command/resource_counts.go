@@ -0,0 +1,33 @@
+package command
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// reApplyChanges matches the summary at the end of the output of an apply.
+var reApplyChanges = regexp.MustCompile(`Apply complete! Resources: (?:(\d+) imported, )?(\d+) added, (\d+) changed, (\d+) destroyed.`)
+
+// ResourceCounts are the number of resources managed by a project before and
+// after an apply.
+type ResourceCounts struct {
+	Before int
+	After  int
+}
+
+// NewResourceCounts returns the counts after applying applyOutput to a
+// project that managed before resources. It returns nil if the output has no
+// apply summary.
+func NewResourceCounts(before int, applyOutput string) *ResourceCounts {
+	m := reApplyChanges.FindStringSubmatch(applyOutput)
+	if m == nil {
+		return nil
+	}
+	imported, _ := strconv.Atoi(m[1])
+	added, _ := strconv.Atoi(m[2])
+	destroyed, _ := strconv.Atoi(m[4])
+	return &ResourceCounts{
+		Before: before,
+		After:  before + imported + added - destroyed,
+	}
+}
@@ -0,0 +1,16 @@
+package command_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestNewResourceCounts(t *testing.T) {
+	Equals(t, &command.ResourceCounts{Before: 12, After: 15},
+		command.NewResourceCounts(12, "null_resource.a: Creating...\nApply complete! Resources: 4 added, 2 changed, 1 destroyed."))
+	Equals(t, &command.ResourceCounts{Before: 3, After: 5},
+		command.NewResourceCounts(3, "Apply complete! Resources: 1 imported, 1 added, 0 changed, 0 destroyed."))
+	Equals(t, (*command.ResourceCounts)(nil), command.NewResourceCounts(3, "Error: apply failed"))
+}
@@ -9,6 +9,23 @@ type Result struct {
 	// deleted. This happens if automerging is enabled and one project has an
 	// error since automerging requires all plans to succeed.
 	PlansDeleted bool
+	// FailureCategory classifies Failure, if set.
+	FailureCategory FailureCategory
+	// UnmetPrerequisites are the checks that haven't passed if
+	// FailureCategory is PrerequisitesFailure.
+	UnmetPrerequisites []string
+	// FirstTime is true if this is the first command the pull request's
+	// author has run.
+	FirstTime bool
+	// CancellationReason is why the command was cancelled, if it was.
+	CancellationReason CancellationReason
+	// RequestID identifies the request that ran the command in the logs, if
+	// known.
+	RequestID string
+	// ChangedFiles and ChangedProjects are the number of files and projects
+	// the pull request changes relative to its base branch, or 0 if unknown.
+	ChangedFiles    int
+	ChangedProjects int
 }
 
 // HasErrors returns true if there were any errors during the execution,
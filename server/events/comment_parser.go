@@ -549,7 +549,8 @@ Commands:
            Approves all current policy checking failures for the PR.
 {{- end }}
 {{- if .AllowVersion }}
-  version  Print the output of 'terraform version'
+  version  Print the output of 'terraform version'.
+           When run for multiple projects, the versions are listed in a table.
 {{- end }}
 {{- if .AllowImport }}
   import ADDRESS ID
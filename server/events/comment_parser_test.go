@@ -877,7 +877,8 @@ Commands:
            To unlock a specific plan you can use the Atlantis UI.
   approve_policies
            Approves all current policy checking failures for the PR.
-  version  Print the output of 'terraform version'
+  version  Print the output of 'terraform version'.
+           When run for multiple projects, the versions are listed in a table.
   import ADDRESS ID
            Runs 'terraform import' for the passed address resource.
            To import a specific project, use the -d, -w and -p flags.
@@ -32,7 +32,7 @@ func TestImportCommandRunner_Run(t *testing.T) {
 				Mergeable:      true,
 			},
 			projectCmds: []command.ProjectContext{},
-			expComment:  "Ran Import for 0 projects:",
+			expComment:  "No projects matched this command.",
 		},
 		{
 			name: "failure with multiple projects",
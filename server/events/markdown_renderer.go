@@ -15,10 +15,19 @@ package events
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/runatlantis/atlantis/server/events/command"
@@ -35,9 +44,114 @@ var (
 	versionCommandTitle         = command.Version.TitleString()
 	importCommandTitle          = command.Import.TitleString()
 	stateCommandTitle           = command.State.TitleString()
+	// defaultNoOutputMessage is the default for NoOutputMessage.
+	defaultNoOutputMessage = "Atlantis has no output to show for this project. This is a bug, please report it."
+	// defaultMaxResourceChanges is the default for MaxResourceChanges.
+	defaultMaxResourceChanges = 25
+	// defaultMaxWarnings is the default for MaxWarnings.
+	defaultMaxWarnings = 10
+	// progressBarWidth is the number of cells in progress bars.
+	progressBarWidth = 10
+	// defaultStalePlanAge is the default for StalePlanAge.
+	defaultStalePlanAge = time.Hour
+	// maxChecksTextLength is the maximum length of the summary and text of a
+	// GitHub check run.
+	maxChecksTextLength = 65535
+	// ParsedPlanView shows the summary of a plan and the resources it
+	// changes.
+	ParsedPlanView = "parsed"
+	// RawPlanView shows the output of terraform plan.
+	RawPlanView = "raw"
+	// SuccessIcon, ErrorIcon, FailureIcon and WarningIcon are the keys of
+	// the default icons in Icons.
+	SuccessIcon = "success"
+	ErrorIcon   = "error"
+	FailureIcon = "failure"
+	WarningIcon = "warning"
+	// labelIconPrefix prefixes project labels in the keys of Icons.
+	labelIconPrefix = "label:"
+	// timestampLayout is the layout of absolute timestamps.
+	timestampLayout = "2006-01-02 15:04 MST"
 	// maxUnwrappedLines is the maximum number of lines the Terraform output
 	// can be before we wrap it in an expandable template.
 	maxUnwrappedLines = 12
+	// reTerraformVersion matches the version in the output of
+	// 'terraform version'.
+	reTerraformVersion = regexp.MustCompile(`Terraform v(\S+)`)
+	// reApplySummary matches the one line summary at the end of the output
+	// of 'terraform apply'.
+	reApplySummary = regexp.MustCompile(`Apply complete! Resources: .*\.`)
+	// reApplyError matches the lines of apply output that report an error,
+	// with or without the box Terraform draws around diagnostics.
+	reApplyError = regexp.MustCompile(`(?m)^[\s│╷]*(Error: .*?)\s*$`)
+	// reInitBackendProblem matches init errors caused by the backend.
+	reInitBackendProblem = regexp.MustCompile(`(?i)error configuring the backend|backend initialization required|backend configuration changed|failed to get existing workspaces|error loading state|error refreshing state`)
+	// reChecksumMismatch matches errors caused by providers that don't
+	// match the checksums in the dependency lock file.
+	reChecksumMismatch = regexp.MustCompile(`(?i)(?:does not|doesn't) match any of the checksums|checksum (?:list has changed|mismatch)`)
+	// reBackendMigration matches errors caused by a changed backend
+	// configuration, which may require migrating the state.
+	reBackendMigration = regexp.MustCompile(`(?i)backend configuration (?:changed|block has changed)|may require migrating existing state|unsetting the previously set backend|terraform init -migrate-state`)
+	// rePreventDestroy matches the error for a resource the plan would
+	// destroy despite lifecycle.prevent_destroy, capturing its address.
+	rePreventDestroy = regexp.MustCompile(`Resource (\S+) has lifecycle\.prevent_destroy set`)
+	// reInitProviderProblem matches init errors caused by installing
+	// providers.
+	reInitProviderProblem = regexp.MustCompile(`(?i)failed to query available provider packages|failed to install provider|could not retrieve the list of available versions|incompatible provider version|inconsistent dependency lock file`)
+	// reBackticks matches runs of backticks.
+	reBackticks = regexp.MustCompile("`+")
+	// reComputedAttribute matches an attribute in a plan whose value is only
+	// known after apply, capturing the line up to the attribute name.
+	reComputedAttribute = regexp.MustCompile(`^(\s*(?:[+~-]\s+)?)\S+\s+=\s+\(known after apply\)\s*$`)
+	// reDataSourceRead matches the progress Terraform prints while reading a
+	// data source, capturing its address.
+	reDataSourceRead = regexp.MustCompile(`^\s*((?:module\.\S+\.)?data\.[^:\s]+): (?:Reading\.\.\.|Still reading\.\.\. .*|Read complete after .*)\s*$`)
+	// reProvisionerLine matches a line of output from a local-exec or
+	// remote-exec provisioner, ex.
+	// "aws_instance.web (remote-exec): Connecting to remote host via SSH...".
+	reProvisionerLine = regexp.MustCompile(`(?m)^\S+ \((?:local|remote)-exec\): `)
+	// reUnlinkable matches the parts of a line that ticket references
+	// aren't linked in: code spans, links and HTML tags.
+	reUnlinkable = regexp.MustCompile("`[^`]*`|\\[[^\\]]*\\]\\([^)]*\\)|<[^>]+>")
+	// reAnchor matches the anchors placed above project sections.
+	reAnchor = regexp.MustCompile(`<a id="[^"]*"></a>\n?`)
+	// reActionItem matches a list item asking the reader to comment a
+	// command or click a link.
+	reActionItem = regexp.MustCompile(`^\s*\* :[a-z_]+: To `)
+	// reTaskListItem matches the checkbox of a task list item.
+	reTaskListItem = regexp.MustCompile(`^(\s*[*-] )\[[ xX]\] `)
+	// reModulePrefix matches the module calls at the start of a resource
+	// address, ex. "module.vpc.module.subnets[0]." and reModuleAddress
+	// matches each of them.
+	reModulePrefix  = regexp.MustCompile(`^(?:module\.[\w-]+(?:\[[^\]]*\])?\.)+`)
+	reModuleAddress = regexp.MustCompile(`module\.[\w-]+(?:\[[^\]]*\])?`)
+	// reModuleSource matches a possible git module source, which is only
+	// shortened if it has a "git::" prefix or ".git" suffix.
+	reModuleSource = regexp.MustCompile(`(git::)?(?:(?:ssh|https)://)?(?:git@)?([\w-]+(?:\.[\w-]+)+)[:/]([\w.-]+(?:/[\w-]+)*?)(\.git)?(//[\w./-]+?)?(?:\?ref=([\w./-]+))?(["\s]|$)`)
+	// reJSONAttribute matches an attribute in a plan whose value is a
+	// single string that may hold JSON, capturing the line up to the value,
+	// the attribute name and the quoted value.
+	reJSONAttribute = regexp.MustCompile(`^(\s*(?:[+~-]\s+)?(\S+)\s+=\s+)("[{\[].*[}\]]")$`)
+	// minJSONValueLength is the length a JSON value must be to be moved out
+	// of the diff by PrettyPrintJSON.
+	minJSONValueLength = 60
+	// reAnchorUnsafe matches runs of characters that can't be used in HTML
+	// anchor ids.
+	reAnchorUnsafe = regexp.MustCompile(`[^a-z0-9_-]+`)
+	// reFoldTag matches the opening tag of a collapsible section, including
+	// its summary, or its closing tag.
+	reFoldTag = regexp.MustCompile(`<details><summary>(.*?)</summary>|<details>|</details>`)
+	// reBlockTag matches the opening or closing tag of a collapsible section
+	// or a div, ex. the one rendering a comment right to left.
+	reBlockTag = regexp.MustCompile(`<(/?)(details|div)\b[^>]*>`)
+	// reDiffChangedLine matches a line of a plan that adds, changes or
+	// removes something.
+	reDiffChangedLine = regexp.MustCompile(`^\s*(-/\+|\+/-|<=|[+\-~!])(\s|$)`)
+	// reChecksumMarker matches the marker added by EmbedChecksum.
+	reChecksumMarker = regexp.MustCompile(`\s*<!-- atlantis-checksum: [0-9a-f]+ -->`)
+	// reVolatileTimestamp matches timestamps that change between renders of
+	// the same content, like "5m ago", "1h5m ago" or "2006-01-02T15:04:05Z".
+	reVolatileTimestamp = regexp.MustCompile(`\b(\d+\s*(s|m|h|d|secs?|seconds?|mins?|minutes?|hours?|days?)\s*)+ago\b|\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}(:\d{2})?(\.\d+)?(Z|[+-]\d{2}:?\d{2}| UTC)?`)
 
 	//go:embed templates/*
 	templatesFS embed.FS
@@ -57,6 +171,230 @@ type MarkdownRenderer struct {
 	markdownTemplates         *template.Template
 	executableName            string
 	hideUnchangedPlanComments bool
+	// projectTemplates are the templates registered with
+	// RegisterProjectTemplate, keyed by project label.
+	projectTemplates map[string]*template.Template
+
+	MarkdownRendererOptions
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// MarkdownRendererOptions configure the optional parts of the comments
+// MarkdownRenderer renders. The zero value renders the default comments.
+type MarkdownRendererOptions struct {
+	// ShowPlanDiffLineCount adds the number of lines in each project's plan
+	// output to its section header in multi-project plan comments.
+	ShowPlanDiffLineCount bool
+	// ReverseSectionOrder renders the per-project sections of multi-project
+	// plan comments newest-first. The directory list keeps its order.
+	ReverseSectionOrder bool
+	// SecretMaskPatterns are matched against plan and apply output and every
+	// match is replaced with "***" before the output is rendered.
+	SecretMaskPatterns []*regexp.Regexp
+	// ProjectPathRoot, if set, is trimmed from the start of project
+	// directories under it when displaying them. The full directory is kept
+	// as a hover title.
+	ProjectPathRoot string
+	// LogSummary is the title of the verbose log section. Defaults to "Log".
+	LogSummary string
+	// ExpandLog renders the verbose log below a separator instead of in a
+	// collapsed section.
+	ExpandLog bool
+	// Minimal renders only the command, its overall status and a one-line
+	// summary per project, without any output, links or logs.
+	Minimal bool
+	// ShowApplySnippet renders the comment that applies each plan in a code
+	// block so it can be copied.
+	ShowApplySnippet bool
+	// DirectoryTableThreshold is the number of projects above which the
+	// directory list of multi-project comments is rendered as a table linking
+	// to each project's section. 0 disables the table.
+	DirectoryTableThreshold int
+	// DirectoryTableColumns is the number of columns of the directory table.
+	// Defaults to 2.
+	DirectoryTableColumns int
+	// MaxFoldDepth is the maximum number of nested collapsible sections.
+	// Collapsible sections nested deeper are rendered as plain sections
+	// since some VCS hosts don't render them reliably. 0 means unlimited.
+	MaxFoldDepth int
+	// InlineNoChanges annotates plans without changes in the directory list
+	// of multi-project plan comments instead of rendering their sections.
+	InlineNoChanges bool
+	// CodeFence is the string used to open and close code blocks, ex. "~~~".
+	// Defaults to "```".
+	CodeFence string
+	// ShowProjectAnchors renders an HTML anchor with a stable id, derived
+	// from the project's directory and workspace, before each project
+	// section so that it can be linked to from outside the comment.
+	ShowProjectAnchors bool
+	// ShowNextSteps appends a line suggesting what to do next, based on
+	// the command that was run and whether it succeeded.
+	ShowNextSteps bool
+	// CollapseDiffContext shows only the changed lines of a plan, plus
+	// DiffContextLines lines of context around them, with the full plan
+	// available in a collapsible section.
+	CollapseDiffContext bool
+	// DiffContextLines is the number of unchanged lines kept around each
+	// changed line when CollapseDiffContext is enabled.
+	DiffContextLines int
+	// SuccessMessages maps commands to a message rendered above the output
+	// of each project the command succeeded for.
+	SuccessMessages map[command.Name]string
+	// NoOutputMessage is rendered, along with the command and project, when
+	// there is no output to render for a project. Defaults to
+	// defaultNoOutputMessage.
+	NoOutputMessage string
+	// EmbedChecksum appends a hidden marker with the ContentChecksum of the
+	// comment so that callers can skip updating a comment that hasn't
+	// changed.
+	EmbedChecksum bool
+	// ShowResourceChanges lists the resources each plan changes below the
+	// plan output.
+	ShowResourceChanges bool
+	// MaxResourceChanges is the number of changed resources listed before
+	// the rest are collapsed. Defaults to defaultMaxResourceChanges.
+	MaxResourceChanges int
+	// ShowStatusTable renders a table with the status and changes of each
+	// project before the sections of multi-project comments.
+	ShowStatusTable bool
+	// StatusEmoji prefixes the statuses in the status table with emoji.
+	StatusEmoji bool
+	// MaxCommentLength is the maximum length of a comment. Longer comments
+	// are truncated and end with a footer saying so. 0 means no limit.
+	MaxCommentLength int
+	// CommentBudget, if set, shares MaxCommentLength between the sections of
+	// a comment and truncates each section to its share.
+	CommentBudget *CommentBudget
+	// CollapseComputedAttributes replaces runs of attributes that are
+	// "(known after apply)" in plans with a count of them.
+	CollapseComputedAttributes bool
+	// ShowWelcome prepends WelcomeMessage to comments for first-time
+	// contributors.
+	ShowWelcome bool
+	// WelcomeMessage greets first-time contributors. Defaults to a short
+	// explanation of the Atlantis workflow.
+	WelcomeMessage string
+	// ShortenModuleSources shortens git module sources in plans, ex.
+	// "git::ssh://git@github.com/org/modules.git//vpc?ref=v1.0.0" to
+	// "github.com/org/modules//vpc@v1.0.0".
+	ShortenModuleSources bool
+	// Locale is the language of the templates, ex. of translated templates
+	// in the overrides directory. Comments in right-to-left languages, ex.
+	// "ar" or "he", render their prose right to left while code blocks stay
+	// left to right.
+	Locale string
+	// PlanView is the view of each plan shown expanded, either
+	// ParsedPlanView or RawPlanView, with the other view collapsed below it.
+	// If empty only the raw plan is shown.
+	PlanView string
+	// DisableNewlineNormalization keeps CRLF and CR line endings in output,
+	// ex. from Terraform running on Windows, instead of converting them to
+	// LF.
+	DisableNewlineNormalization bool
+	// ShowResourceStats shows the number of attribute lines added and
+	// removed next to each resource listed by ShowResourceChanges.
+	ShowResourceStats bool
+	// WrapInRegionMarkers wraps the comment in RegionStartMarker and
+	// RegionEndMarker so that it can be found and replaced within a larger
+	// comment.
+	WrapInRegionMarkers bool
+	// PrettyPrintJSON moves long JSON strings in plans, ex. minified policy
+	// documents, out of the diff into collapsible, pretty-printed blocks.
+	PrettyPrintJSON bool
+	// Quiet renders an empty comment, meaning it shouldn't be posted, for
+	// plans that succeeded without changes in every project.
+	Quiet bool
+	// GroupResourceChangesByModule groups the resources listed by
+	// ShowResourceChanges under the module that contains them.
+	GroupResourceChangesByModule bool
+	// MaxModuleDepth is the deepest module nesting that gets its own group
+	// when GroupResourceChangesByModule is enabled. Resources in deeper
+	// modules are listed under their ancestor at that depth. 0 means
+	// unlimited.
+	MaxModuleDepth int
+	// PrintFriendly renders comments for conversion to PDF or print: all
+	// collapsible sections are expanded, and the instructions to comment
+	// commands or click links, anchors and task list checkboxes are
+	// removed.
+	PrintFriendly bool
+	// StalePlanAge is the PlanAge beyond which a result warns that it used
+	// an old plan. Defaults to defaultStalePlanAge.
+	StalePlanAge time.Duration
+	// Icons overrides the icons rendered for statuses. Keys are
+	// SuccessIcon, ErrorIcon, FailureIcon and WarningIcon, a
+	// command.FailureCategory to override FailureIcon for failures of that
+	// category, or "label:" followed by a project label to override the
+	// icon of projects with that label. Unmapped keys use defaultIcons.
+	Icons map[string]string
+	// ShowGeneratedAt ends comments with the time they were generated.
+	ShowGeneratedAt bool
+	// Location is the time zone absolute timestamps are rendered in.
+	// Defaults to UTC.
+	Location *time.Location
+	// CollapseProvisionerOutput collapses the output of local-exec and
+	// remote-exec provisioners in apply output, keeping the lines about
+	// the resources visible.
+	CollapseProvisionerOutput bool
+	// GroupByOwner groups the sections of multi-project comments under a
+	// heading for the Owner of their projects.
+	GroupByOwner bool
+	// ShowDebugInfo ends comments with a collapsible section listing the
+	// Terraform version, workspace and working directory of each project.
+	ShowDebugInfo bool
+	// TicketLinks link the ticket references in comments, outside of code,
+	// to their tracker.
+	TicketLinks []TicketLink
+	// ThreadKey is rendered as the first line of comments, ex. an HTML
+	// comment that a VCS host threads or groups comments by. It counts
+	// toward MaxCommentLength.
+	ThreadKey string
+	// RunbookURL is linked in the header of comments if set, ex. the repo's
+	// runbook for handling failed plans and applies.
+	RunbookURL string
+	// ForceMultiLayout renders results with the multi-project templates even
+	// if there's only one project, ex. for consistent anchors.
+	ForceMultiLayout bool
+	// SortResourceChangesBySeverity lists the resources listed by
+	// ShowResourceChanges by the severity of their action, destroys first,
+	// rather than by address.
+	SortResourceChangesBySeverity bool
+	// MaxWarnings is the number of deprecations and authentication notices
+	// listed for each plan before the rest are collapsed. Defaults to
+	// defaultMaxWarnings.
+	MaxWarnings int
+	// CollapseDataSourceReads replaces the lines plans print while reading
+	// data sources with a count of the data sources read, keeping the
+	// resource changes visible.
+	CollapseDataSourceReads bool
+	// KnownIssues link errors matching their Pattern to an article about
+	// them, ex. in a support knowledge base. The first match is linked.
+	KnownIssues []KnownIssue
+}
+
+// TicketLink links references to tickets matching Pattern, ex. "JIRA-123",
+// to URL. URL is expanded like regexp.Regexp.Expand, so "$0" is the whole
+// reference and "$1" its first submatch, ex.
+// "https://jira.example.com/browse/$0".
+type TicketLink struct {
+	Pattern *regexp.Regexp
+	URL     string
+}
+
+// KnownIssue is an error matching Pattern, ex. a common misconfiguration,
+// that the article at URL explains.
+type KnownIssue struct {
+	Pattern *regexp.Regexp
+	URL     string
+}
+
+// defaultIcons are the icons rendered for statuses not in Icons.
+var defaultIcons = map[string]string{
+	SuccessIcon: "✅",
+	ErrorIcon:   "❌",
+	FailureIcon: "❌",
+	WarningIcon: "⚠️",
 }
 
 // commonData is data that all responses have.
@@ -72,12 +410,66 @@ type commonData struct {
 	EnableDiffMarkdownFormat  bool
 	ExecutableName            string
 	HideUnchangedPlanComments bool
+	ReverseSectionOrder       bool
+	LogSummary                string
+	ExpandLog                 bool
+	InlineNoChanges           bool
+	FirstTime                 bool
+	RequestID                 string
+	ChangedFiles              int
+	ChangedProjects           int
+}
+
+// cancelledData is data about a cancelled command.
+type cancelledData struct {
+	Reason command.CancellationReason
+	commonData
+}
+
+// welcomeData is data about the greeting for first-time contributors.
+type welcomeData struct {
+	Message string
+	commonData
+}
+
+// nextStepsData is data about the next steps after a command.
+type nextStepsData struct {
+	CommandName string
+	HasErrors   bool
+	commonData
 }
 
 // errData is data about an error response.
 type errData struct {
 	Error           string
 	RenderedContext string
+	// ExitCode is the exit code of the command that caused the error, or 0
+	// if it's unknown.
+	ExitCode int
+	// Phase is the phase of the command the error occurred in, ex. "init",
+	// if it's known.
+	Phase string
+	// InitProblem is "upgrade", "backend" or "provider" if an init error was
+	// caused by outdated dependencies, the backend or installing providers.
+	InitProblem string
+	// ChecksumMismatch is true if the error was caused by providers that
+	// don't match the checksums in the dependency lock file.
+	ChecksumMismatch bool
+	// BackendMigration is true if the error was caused by a changed backend
+	// configuration, which may require migrating the state.
+	BackendMigration bool
+	// ProtectedResources are the addresses of the resources the plan
+	// would destroy despite lifecycle.prevent_destroy.
+	ProtectedResources []string
+	// KnownIssueURL links to the article about the first of KnownIssues the
+	// error matches, if any.
+	KnownIssueURL string
+	// Wrapped is true if the error should be rendered in a collapsible
+	// section.
+	Wrapped bool
+	// Headline is the first meaningful line of a multi-line error, rendered
+	// above the collapsed full error.
+	Headline string
 	commonData
 }
 
@@ -85,11 +477,21 @@ type errData struct {
 type failureData struct {
 	Failure         string
 	RenderedContext string
+	Category        command.FailureCategory
+	// UnmetPrerequisites are the checks that haven't passed for a
+	// prerequisites failure.
+	UnmetPrerequisites []string
 	commonData
 }
 
 type resultData struct {
 	Results []projectResultTmplData
+	// DirectoryTable is the directory list rendered as a table, if the
+	// number of results is above DirectoryTableThreshold.
+	DirectoryTable string
+	// StatusTable is the table of project statuses, if ShowStatusTable is
+	// enabled.
+	StatusTable string
 	commonData
 }
 
@@ -101,6 +503,83 @@ type planSuccessData struct {
 	DisableRepoLocking       bool
 	EnableDiffMarkdownFormat bool
 	PlanStats                models.PlanSuccessStats
+	Deprecations             []string
+	AuthNotices              []string
+	TaintedResources         []string
+	ResourceMoves            []models.ResourceMove
+	SensitiveChanges         []models.SensitiveChange
+	Providers                []models.ProviderVersion
+	WorkspaceNotices         []string
+	RepoRelDir               string
+	Workspace                string
+	ProjectName              string
+	// ApplySnippet is the comment to apply this plan. It is only set when
+	// ShowApplySnippet is enabled.
+	ApplySnippet string
+	// TrimmedDiff is the plan with unchanged context trimmed. It is only set
+	// when CollapseDiffContext is enabled and some context was trimmed.
+	TrimmedDiff string
+	// ResourceChanges are the changes listed when ShowResourceChanges is
+	// enabled, and HiddenResourceChanges are the ones beyond
+	// MaxResourceChanges.
+	ResourceChanges       []models.ResourceChange
+	HiddenResourceChanges []models.ResourceChange
+	ShowResourceStats     bool
+	// ModuleGroups are ResourceChanges grouped by module. They are only set
+	// when GroupResourceChangesByModule is enabled.
+	ModuleGroups []moduleGroup
+	// EstApplyTime is EstApplyDuration formatted for display.
+	EstApplyTime string
+	// NeedsInitUpgrade is true if the plan output suggests running
+	// 'terraform init -upgrade'.
+	NeedsInitUpgrade bool
+	// JSONValues are the JSON strings moved out of the diff by
+	// PrettyPrintJSON.
+	JSONValues []jsonValue
+	// DestroyedResources are the addresses of the resources the plan
+	// destroys, including the ones it replaces.
+	DestroyedResources []string
+	// PlanView and PlanChanges are set when PlanView is enabled.
+	PlanView    string
+	PlanChanges string
+	// ChangePercent is the share of ManagedResources that the plan changes
+	// or destroys, ex. "15%". It is empty if ManagedResources is unknown.
+	ChangePercent string
+	// HiddenDeprecations and HiddenAuthNotices are the Deprecations and
+	// AuthNotices beyond MaxWarnings.
+	HiddenDeprecations []string
+	HiddenAuthNotices  []string
+	// ApprovalsNeeded is the number of approvals still needed before the
+	// plan can be applied.
+	ApprovalsNeeded int
+}
+
+// jsonValue is a JSON string attribute of a plan, pretty-printed.
+type jsonValue struct {
+	Attribute string
+	JSON      string
+}
+
+type applySuccessData struct {
+	Output string
+	// Errors are the error lines found in Output.
+	Errors []string
+	// ResourceCounts are the number of resources before and after the
+	// apply, if known.
+	ResourceCounts *command.ResourceCounts
+	// Segments are Output split around provisioner output. They are only
+	// set when CollapseProvisionerOutput is enabled and Output has any.
+	Segments []outputSegment
+	// Downstream is the pipeline the apply triggered, if any.
+	Downstream *command.DownstreamPipeline
+}
+
+// outputSegment is a run of lines of apply output.
+type outputSegment struct {
+	Output string
+	// Provisioner is true if the lines are provisioner output.
+	Provisioner bool
+	Lines       int
 }
 
 type policyCheckResultsData struct {
@@ -113,12 +592,179 @@ type policyCheckResultsData struct {
 	commonData
 }
 
+type minimalData struct {
+	Error        string
+	Failure      string
+	Results      []minimalResultData
+	NumSuccesses int
+	// NumLocked is the number of projects that failed because they're
+	// locked.
+	NumLocked int
+	commonData
+}
+
+type minimalResultData struct {
+	projectResultTmplData
+	Succeeded bool
+	Summary   string
+	// Icon is the icon for the result's status.
+	Icon string
+}
+
 type projectResultTmplData struct {
 	Workspace   string
 	RepoRelDir  string
 	ProjectName string
 	Rendered    string
 	NoChanges   bool
+	// DisplayDir is RepoRelDir relative to ProjectPathRoot. It is empty if
+	// the directory isn't trimmed.
+	DisplayDir string
+	// Num is the 1-based position of the result in the comment.
+	Num int
+	// Anchor is the id of the HTML anchor rendered before the project's
+	// section, if any.
+	Anchor string
+	// DiffLines is the number of lines in the plan output. It is only set
+	// for plan results when ShowPlanDiffLineCount is enabled.
+	DiffLines int
+	// TerraformVersion is the version parsed from the output of a version
+	// command, if any.
+	TerraformVersion string
+	// Labels are rendered as badges in the project's header.
+	Labels []string
+	// Team is the heading rendered above the project's section when it's
+	// the first section of its owner's group with GroupByOwner.
+	Team string
+	// Hidden is true if the project's section is left out of multi-project
+	// plan comments, ex. because it has no changes and
+	// HideUnchangedPlanComments is enabled.
+	Hidden bool
+}
+
+// LockSummary describes a lock held by a pull request for rendering in the
+// locks overview.
+type LockSummary struct {
+	ProjectName string
+	RepoRelDir  string
+	Workspace   string
+	// LockURL is the full URL where the lock can be released.
+	LockURL string
+}
+
+type locksOverviewData struct {
+	Locks []LockSummary
+}
+
+type planComparisonData struct {
+	// Removed are the resource changes in the previous plan that aren't in
+	// the current one.
+	Removed []models.ResourceChange
+	// Added are the resource changes in the current plan that weren't in the
+	// previous one.
+	Added []models.ResourceChange
+	// OutputChanged is true if the output of the plans differs.
+	OutputChanged bool
+}
+
+// EnvironmentResult are the results of running a command for the projects of
+// one environment in a promotion.
+type EnvironmentResult struct {
+	Name    string
+	Results []command.ProjectResult
+}
+
+type promotionData struct {
+	Command      string
+	Environments []promotionEnvData
+}
+
+type promotionEnvData struct {
+	Name     string
+	Status   string
+	Projects int
+	Changes  string
+}
+
+type prSummaryData struct {
+	Rows []prSummaryRow
+}
+
+// prSummaryRow is the status of the latest plan, policy check and apply of a
+// project, or "-" for commands that weren't run.
+type prSummaryRow struct {
+	Project     string
+	Workspace   string
+	Plan        string
+	PolicyCheck string
+	Apply       string
+}
+
+type fmtCheckData struct {
+	models.FmtCheckResult
+	RepoRelDir string
+}
+
+type duplicateProjectData struct {
+	RepoRelDir string
+	Workspace  string
+	Count      int
+}
+
+type queueStatusData struct {
+	Position int
+	Ahead    []models.PullRequest
+}
+
+// projectTemplateData is the data of the templates registered with
+// RegisterProjectTemplate.
+type projectTemplateData struct {
+	command.ProjectResult
+	// Rendered is the project's section as it's rendered by default.
+	Rendered string
+}
+
+type debugInfoData struct {
+	// CommandName is the name of the command as it's commented, ex.
+	// "policy_check".
+	CommandName string
+	Results     []command.ProjectResult
+	commonData
+}
+
+type progressData struct {
+	Verb      string
+	Completed int
+	Total     int
+	Bar       string
+	Percent   int
+	// Remaining is the estimated time left, or empty if unknown.
+	Remaining string
+}
+
+const (
+	// RegionStartMarker starts comments when WrapInRegionMarkers is enabled.
+	RegionStartMarker = "<!-- atlantis-start -->"
+	// RegionEndMarker ends comments when WrapInRegionMarkers is enabled.
+	RegionEndMarker = "<!-- atlantis-end -->"
+)
+
+// CommentBudget is the share of MaxCommentLength given to each section of a
+// comment, as fractions of it. Diff is shared by the plan and apply outputs,
+// and Log by the log. The rest is left for the summary, ex. headers and
+// instructions.
+type CommentBudget struct {
+	Diff float64
+	Log  float64
+}
+
+// ChecksPayload is the output of a command formatted for a GitHub check run.
+type ChecksPayload struct {
+	Title   string
+	Summary string
+	Text    string
+	// Annotations locate the errors of the projects in the repo.
+	Annotations []Annotation
 }
 
 // Initialize templates
@@ -132,29 +778,166 @@ func NewMarkdownRenderer(
 	markdownTemplateOverridesDir string,
 	executableName string,
 	hideUnchangedPlanComments bool,
+	opts MarkdownRendererOptions,
 ) *MarkdownRenderer {
-	var templates *template.Template
-	templates, _ = template.New("").Funcs(sprig.TxtFuncMap()).ParseFS(templatesFS, "templates/*.tmpl")
-	if overrides, err := templates.ParseGlob(fmt.Sprintf("%s/*.tmpl", markdownTemplateOverridesDir)); err == nil {
-		// doesn't override if templates directory doesn't exist
-		templates = overrides
-	}
-	return &MarkdownRenderer{
+	m := &MarkdownRenderer{
+		MarkdownRendererOptions:   opts,
 		gitlabSupportsCommonMark:  gitlabSupportsCommonMark,
 		disableApplyAll:           disableApplyAll,
 		disableMarkdownFolding:    disableMarkdownFolding,
 		disableApply:              disableApply,
 		disableRepoLocking:        disableRepoLocking,
 		enableDiffMarkdownFormat:  enableDiffMarkdownFormat,
-		markdownTemplates:         templates,
 		executableName:            executableName,
 		hideUnchangedPlanComments: hideUnchangedPlanComments,
 	}
+	funcs := sprig.TxtFuncMap()
+	funcs["fence"] = m.codeFence
+	funcs["address"] = codeSpan
+	funcs["code"] = codeSpan
+	funcs["icon"] = m.icon
+	var templates *template.Template
+	templates, _ = template.New("").Funcs(funcs).ParseFS(templatesFS, "templates/*.tmpl")
+	if overrides, err := templates.ParseGlob(fmt.Sprintf("%s/*.tmpl", markdownTemplateOverridesDir)); err == nil {
+		// doesn't override if templates directory doesn't exist
+		templates = overrides
+	}
+	m.markdownTemplates = templates
+	return m
+}
+
+// icon returns the icon for key from Icons, or its default.
+func (m *MarkdownRenderer) icon(key string) string {
+	if icon, ok := m.Icons[key]; ok {
+		return icon
+	}
+	return defaultIcons[key]
+}
+
+// truncatedFooter returns the footer ending comments that were truncated.
+func (m *MarkdownRenderer) truncatedFooter() string {
+	return m.icon(WarningIcon) + " This comment was truncated due to size limits."
+}
+
+// resultIcon returns the icon for the status of result. Icons for its labels
+// take precedence over the one for its failure category, which takes
+// precedence over the one for its status.
+func (m *MarkdownRenderer) resultIcon(result command.ProjectResult) string {
+	for _, label := range result.Labels {
+		if icon, ok := m.Icons[labelIconPrefix+label]; ok {
+			return icon
+		}
+	}
+	switch {
+	case result.Error != nil:
+		return m.icon(ErrorIcon)
+	case result.Failure != "":
+		if icon, ok := m.Icons[string(result.FailureCategory)]; ok && result.FailureCategory != command.UnknownFailure {
+			return icon
+		}
+		return m.icon(FailureIcon)
+	default:
+		return m.icon(SuccessIcon)
+	}
+}
+
+// RegisterProjectTemplate registers text as the template of the sections of
+// projects with label, ex. "type:network". The template can use the
+// built-in templates and is executed with the project's result and its
+// default rendering as Rendered. Projects whose labels have no template
+// registered are rendered by default.
+func (m *MarkdownRenderer) RegisterProjectTemplate(label string, text string) error {
+	templates, err := m.markdownTemplates.Clone()
+	if err != nil {
+		return err
+	}
+	tmpl, err := templates.New(label).Parse(text)
+	if err != nil {
+		return fmt.Errorf("parsing template for label %q: %w", label, err)
+	}
+	if m.projectTemplates == nil {
+		m.projectTemplates = make(map[string]*template.Template)
+	}
+	m.projectTemplates[label] = tmpl
+	return nil
+}
+
+// projectTemplate returns the template registered for the first of labels
+// that has one, or nil if none does.
+func (m *MarkdownRenderer) projectTemplate(labels []string) *template.Template {
+	for _, label := range labels {
+		if tmpl, ok := m.projectTemplates[label]; ok {
+			return tmpl
+		}
+	}
+	return nil
+}
+
+// now returns the current time from Now, or time.Now if it isn't set.
+func (m *MarkdownRenderer) now() time.Time {
+	if m.Now == nil {
+		return time.Now()
+	}
+	return m.Now()
+}
+
+// formatTime formats t as an absolute timestamp in Location.
+func (m *MarkdownRenderer) formatTime(t time.Time) string {
+	loc := m.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(timestampLayout)
+}
+
+// codeFence returns the string opening and closing code blocks.
+func (m *MarkdownRenderer) codeFence() string {
+	if m.CodeFence == "" {
+		return "```"
+	}
+	return m.CodeFence
+}
+
+// codeSpan wraps s, ex. a resource address, in an inline code span so that
+// markdown in it isn't interpreted. If s contains backticks, a longer run of
+// backticks is used as the delimiter.
+func codeSpan(s string) string {
+	longest := 0
+	for _, run := range reBackticks.FindAllString(s, -1) {
+		if len(run) > longest {
+			longest = len(run)
+		}
+	}
+	if longest == 0 {
+		return "`" + s + "`"
+	}
+	delim := strings.Repeat("`", longest+1)
+	return delim + " " + s + " " + delim
 }
 
 // Render formats the data into a markdown string.
 // nolint: interfacer
 func (m *MarkdownRenderer) Render(res command.Result, cmdName command.Name, subCmd, log string, verbose bool, vcsHost models.VCSHostType) string {
+	if m.Quiet && cmdName == command.Plan && isNoChangePlan(res) {
+		return ""
+	}
+	truncated := false
+	if m.CommentBudget != nil && m.MaxCommentLength > 0 {
+		res, log, truncated = m.applyCommentBudget(res, log)
+	}
+	comment := m.renderBody(res, cmdName, subCmd, log, verbose, vcsHost)
+	if m.MaxCommentLength > 0 && len(m.finishComment(comment, truncated)) > m.MaxCommentLength {
+		// Leave room for what finishComment adds around the comment.
+		overhead := len(m.finishComment("", true))
+		comment = truncateComment(comment, m.MaxCommentLength-overhead, m.codeFence())
+		truncated = true
+	}
+	return m.finishComment(comment, truncated)
+}
+
+// renderBody formats the data into a markdown string, without what only
+// comments get, ex. the checksum or the truncation to MaxCommentLength.
+func (m *MarkdownRenderer) renderBody(res command.Result, cmdName command.Name, subCmd, log string, verbose bool, vcsHost models.VCSHostType) string {
 	commandStr := cases.Title(language.English).String(strings.Replace(cmdName.String(), "_", " ", -1))
 	common := commonData{
 		Command:                   commandStr,
@@ -168,20 +951,240 @@ func (m *MarkdownRenderer) Render(res command.Result, cmdName command.Name, subC
 		EnableDiffMarkdownFormat:  m.enableDiffMarkdownFormat,
 		ExecutableName:            m.executableName,
 		HideUnchangedPlanComments: m.hideUnchangedPlanComments,
+		ReverseSectionOrder:       m.ReverseSectionOrder,
+		LogSummary:                m.LogSummary,
+		ExpandLog:                 m.ExpandLog,
+		InlineNoChanges:           m.InlineNoChanges,
+		FirstTime:                 res.FirstTime,
+		RequestID:                 res.RequestID,
+		ChangedFiles:              res.ChangedFiles,
+		ChangedProjects:           res.ChangedProjects,
+	}
+	if common.LogSummary == "" {
+		common.LogSummary = "Log"
+	}
+	if m.PrintFriendly {
+		common.ExpandLog = true
+	}
+	if allPlansSuperseded(res) {
+		// None of the plans can be applied anymore.
+		common.DisableApplyAll = true
+	}
+	comment := m.renderResult(res, cmdName, common, vcsHost)
+	if common.ChangedFiles > 0 || common.ChangedProjects > 0 {
+		comment = m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("prContext"), common) + "\n\n" + comment
+	}
+	if m.ShowWelcome && common.FirstTime {
+		comment = m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("welcome"), welcomeData{
+			Message:    m.WelcomeMessage,
+			commonData: common,
+		}) + "\n\n" + comment
+	}
+	if m.RunbookURL != "" {
+		comment = m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("runbook"), m.RunbookURL) + "\n\n" + comment
+	}
+	if m.ShowNextSteps {
+		if nextSteps := m.renderNextSteps(res, cmdName, common); nextSteps != "" {
+			comment += "\n\n" + nextSteps
+		}
 	}
+	if m.ShowDebugInfo && len(res.ProjectResults) > 0 {
+		comment += "\n\n" + m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("debugInfo"), debugInfoData{
+			CommandName: cmdName.String(),
+			Results:     res.ProjectResults,
+			commonData:  common,
+		})
+	}
+	if common.RequestID != "" && hasErrorOrFailure(res) {
+		comment += "\n\n" + m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("requestID"), common)
+	}
+	if m.ShowGeneratedAt {
+		comment += "\n\n" + m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("generatedAt"), m.formatTime(m.now()))
+	}
+	if m.PrintFriendly {
+		comment = printFriendly(comment, m.codeFence())
+	} else if m.MaxFoldDepth > 0 {
+		comment = flattenFolds(comment, m.MaxFoldDepth, m.codeFence())
+	}
+	if len(m.TicketLinks) > 0 {
+		comment = m.linkifyTickets(comment)
+	}
+	if isRightToLeft(m.Locale) {
+		comment = rightToLeft(comment, m.codeFence())
+	}
+	return comment
+}
+
+// finishComment adds the checksum, footer, region markers and thread key
+// that are enabled to comment. Their length doesn't depend on comment. The
+// footer is kept last within the region markers.
+func (m *MarkdownRenderer) finishComment(comment string, truncated bool) string {
+	if m.EmbedChecksum {
+		comment += fmt.Sprintf("\n\n<!-- atlantis-checksum: %s -->", ContentChecksum(comment))
+	}
+	if truncated {
+		comment += "\n\n" + m.truncatedFooter()
+	}
+	if m.WrapInRegionMarkers {
+		comment = RegionStartMarker + "\n" + comment + "\n" + RegionEndMarker
+	}
+	if m.ThreadKey != "" {
+		comment = m.ThreadKey + "\n" + comment
+	}
+	return comment
+}
+
+// applyCommentBudget truncates the outputs in res and log to their shares of
+// MaxCommentLength under CommentBudget. It returns true if any was
+// truncated. The outputs are copied rather than truncated in place.
+func (m *MarkdownRenderer) applyCommentBudget(res command.Result, log string) (command.Result, string, bool) {
+	truncated := false
+	if limit := int(m.CommentBudget.Log * float64(m.MaxCommentLength)); len(log) > limit {
+		log = truncateSection(log, limit-1) + "\n"
+		truncated = true
+	}
+
+	outputs := 0
+	for _, r := range res.ProjectResults {
+		if r.PlanSuccess != nil || r.ApplySuccess != "" {
+			outputs++
+		}
+	}
+	if outputs == 0 {
+		return res, log, truncated
+	}
+	limit := int(m.CommentBudget.Diff*float64(m.MaxCommentLength)) / outputs
+	results := make([]command.ProjectResult, len(res.ProjectResults))
+	copy(results, res.ProjectResults)
+	for i, r := range results {
+		// Secrets are masked before truncating so that a secret cut in
+		// two is still matched.
+		if r.PlanSuccess != nil {
+			planSuccess := *r.PlanSuccess
+			planSuccess.TerraformOutput = m.maskSecrets(planSuccess.TerraformOutput)
+			if len(planSuccess.TerraformOutput) > limit {
+				// Keep the summary of changes since it's parsed for the rest
+				// of the comment.
+				summary := planSuccess.DiffSummary()
+				if !strings.Contains(planSuccess.TerraformOutput, summary) {
+					summary = ""
+				}
+				planSuccess.TerraformOutput = truncateSection(planSuccess.TerraformOutput, limit-len(summary)-1)
+				if summary != "" {
+					planSuccess.TerraformOutput += "\n" + summary
+				}
+				truncated = true
+			}
+			results[i].PlanSuccess = &planSuccess
+		}
+		results[i].ApplySuccess = m.maskSecrets(r.ApplySuccess)
+		if len(results[i].ApplySuccess) > limit {
+			results[i].ApplySuccess = truncateSection(results[i].ApplySuccess, limit)
+			truncated = true
+		}
+	}
+	res.ProjectResults = results
+	return res, log, truncated
+}
+
+// truncateSection cuts s at a line boundary so that it's at most limit long,
+// ending it with a line of "..." to show where it was cut.
+func truncateSection(s string, limit int) string {
+	const marker = "..."
+	cut := limit - len(marker) - 1
+	if cut <= 0 {
+		return marker
+	}
+	i := strings.LastIndex(s[:cut+1], "\n")
+	if i <= 0 {
+		return marker
+	}
+	return strings.TrimRight(s[:i], "\n") + "\n" + marker
+}
+
+// isNoChangePlan returns true if res is a plan of at least one project that
+// succeeded without changes in every project.
+func isNoChangePlan(res command.Result) bool {
+	if hasErrorOrFailure(res) || res.CancellationReason != command.NotCancelled || len(res.ProjectResults) == 0 {
+		return false
+	}
+	for _, r := range res.ProjectResults {
+		if r.PlanSuccess == nil || !r.PlanSuccess.NoChanges() || r.CancellationReason != command.NotCancelled {
+			return false
+		}
+	}
+	return true
+}
+
+// hasErrorOrFailure returns true if res or any of its project results has an
+// error or failure. Unlike res.HasErrors results without a plan or apply,
+// ex. of a version command, aren't counted.
+func hasErrorOrFailure(res command.Result) bool {
+	if res.Error != nil || res.Failure != "" {
+		return true
+	}
+	for _, r := range res.ProjectResults {
+		if r.Error != nil || r.Failure != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentChecksum returns a hash of a rendered comment that only changes if
+// its logical content changes. Volatile parts like relative timestamps and
+// any embedded checksum are ignored.
+func ContentChecksum(comment string) string {
+	normalized := reChecksumMarker.ReplaceAllString(comment, "")
+	normalized = reVolatileTimestamp.ReplaceAllString(normalized, "<time>")
+	sum := sha256.Sum256([]byte(strings.TrimSpace(normalized)))
+	return hex.EncodeToString(sum[:])
+}
+
+// renderNextSteps renders the step to take after running cmdName, or an
+// empty string if there's nothing to suggest.
+func (m *MarkdownRenderer) renderNextSteps(res command.Result, cmdName command.Name, common commonData) string {
+	hasErrors := res.HasErrors()
+	if !hasErrors && (len(res.ProjectResults) == 0 || (cmdName == command.Plan && common.DisableApply)) {
+		return ""
+	}
+	return m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("nextSteps"), nextStepsData{
+		CommandName: cmdName.String(),
+		HasErrors:   hasErrors,
+		commonData:  common,
+	})
+}
 
+// renderResult renders res before any post-processing of the comment.
+func (m *MarkdownRenderer) renderResult(res command.Result, cmdName command.Name, common commonData, vcsHost models.VCSHostType) string {
 	templates := m.markdownTemplates
 
+	if m.Minimal {
+		return m.renderMinimal(res, common)
+	}
+	if res.CancellationReason != command.NotCancelled {
+		return m.renderTemplateTrimSpace(templates.Lookup("cancelled"), cancelledData{
+			Reason:     res.CancellationReason,
+			commonData: common,
+		})
+	}
 	if res.Error != nil {
-		return m.renderTemplateTrimSpace(templates.Lookup("unwrappedErrWithLog"), errData{res.Error.Error(), "", common})
+		data := m.newErrData(res.Error, "", common)
+		data.Headline = errorHeadline(data.Error)
+		return m.renderErr(templates.Lookup("unwrappedErrWithLog"), data)
 	}
 	if res.Failure != "" {
-		return m.renderTemplateTrimSpace(templates.Lookup("failureWithLog"), failureData{res.Failure, "", common})
+		return m.renderTemplateTrimSpace(templates.Lookup("failureWithLog"), failureData{
+			Failure:            res.Failure,
+			Category:           res.FailureCategory,
+			UnmetPrerequisites: res.UnmetPrerequisites,
+			commonData:         common,
+		})
 	}
-	return m.renderProjectResults(res.ProjectResults, common, vcsHost)
+	return m.renderProjectResults(res.ProjectResults, cmdName, common, vcsHost)
 }
 
-func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult, common commonData, vcsHost models.VCSHostType) string {
+func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult, cmdName command.Name, common commonData, vcsHost models.VCSHostType) string {
 	var resultsTmplData []projectResultTmplData
 	numPlanSuccesses := 0
 	numPolicyCheckSuccesses := 0
@@ -190,14 +1193,46 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 
 	templates := m.markdownTemplates
 
-	for _, result := range results {
+	useDirectoryTable := m.DirectoryTableThreshold > 0 && len(results) > m.DirectoryTableThreshold
+	if m.GroupByOwner {
+		results = sortByOwner(results)
+	}
+
+	for i, result := range results {
 		resultData := projectResultTmplData{
 			Workspace:   result.Workspace,
 			RepoRelDir:  result.RepoRelDir,
 			ProjectName: result.ProjectName,
+			DisplayDir:  m.displayDir(result.RepoRelDir),
+			Num:         i + 1,
+			Labels:      result.Labels,
+		}
+		noOutput := false
+		fmtCheckRendered := false
+		if useDirectoryTable || m.ShowProjectAnchors {
+			resultData.Anchor = projectAnchor(result.RepoRelDir, result.Workspace)
+		}
+		// The outputs are rewritten below, so they're copied to leave the
+		// caller's result unchanged, ex. for rendering it again.
+		copyResultOutputs(&result)
+		if !m.DisableNewlineNormalization {
+			normalizeResultNewlines(&result)
 		}
 		if result.PlanSuccess != nil {
-			result.PlanSuccess.TerraformOutput = strings.TrimSpace(result.PlanSuccess.TerraformOutput)
+			result.PlanSuccess.TerraformOutput = strings.TrimSpace(m.maskSecrets(result.PlanSuccess.TerraformOutput))
+			if m.ShortenModuleSources {
+				result.PlanSuccess.TerraformOutput = shortenModuleSources(result.PlanSuccess.TerraformOutput)
+			}
+			if m.CollapseComputedAttributes {
+				result.PlanSuccess.TerraformOutput = collapseComputedAttributes(result.PlanSuccess.TerraformOutput)
+			}
+			if m.CollapseDataSourceReads {
+				result.PlanSuccess.TerraformOutput = collapseDataSourceReads(result.PlanSuccess.TerraformOutput)
+			}
+			var jsonValues []jsonValue
+			if m.PrettyPrintJSON {
+				result.PlanSuccess.TerraformOutput, jsonValues = extractJSONValues(result.PlanSuccess.TerraformOutput)
+			}
 			data := planSuccessData{
 				PlanSuccess:              *result.PlanSuccess,
 				PlanWasDeleted:           common.PlansDeleted,
@@ -205,14 +1240,90 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 				DisableRepoLocking:       common.DisableRepoLocking,
 				EnableDiffMarkdownFormat: common.EnableDiffMarkdownFormat,
 				PlanStats:                result.PlanSuccess.Stats(),
+				Deprecations:             result.PlanSuccess.Deprecations(),
+				AuthNotices:              result.PlanSuccess.AuthNotices(),
+				TaintedResources:         result.PlanSuccess.TaintedResources(),
+				ResourceMoves:            result.PlanSuccess.ResourceMoves(),
+				SensitiveChanges:         result.PlanSuccess.SensitiveChanges(),
+				NeedsInitUpgrade:         models.NeedsInitUpgrade(result.PlanSuccess.TerraformOutput),
+				Providers:                result.PlanSuccess.Providers(),
+				WorkspaceNotices:         result.PlanSuccess.WorkspaceNotices(),
+				RepoRelDir:               result.RepoRelDir,
+				Workspace:                result.Workspace,
+				ProjectName:              result.ProjectName,
+				JSONValues:               jsonValues,
+				ApprovalsNeeded:          result.PlanSuccess.ApprovalsNeeded(),
+			}
+			if data.PlanStats.Destroy > 0 {
+				for _, change := range result.PlanSuccess.ResourceChanges() {
+					if change.Action == models.DeleteResourceAction || change.Action == models.ReplaceResourceAction {
+						data.DestroyedResources = append(data.DestroyedResources, change.Address)
+					}
+				}
 			}
-			if m.shouldUseWrappedTmpl(vcsHost, result.PlanSuccess.TerraformOutput) {
+			if result.PlanSuccess.ManagedResources > 0 && data.PlanStats.Changes {
+				data.ChangePercent = changePercent(data.PlanStats, result.PlanSuccess.ManagedResources)
+			}
+			maxWarnings := m.MaxWarnings
+			if maxWarnings <= 0 {
+				maxWarnings = defaultMaxWarnings
+			}
+			data.Deprecations, data.HiddenDeprecations = capWarnings(data.Deprecations, maxWarnings)
+			data.AuthNotices, data.HiddenAuthNotices = capWarnings(data.AuthNotices, maxWarnings)
+			if result.PlanSuccess.EstApplyDuration > 0 {
+				data.EstApplyTime = approxDuration(result.PlanSuccess.EstApplyDuration)
+			}
+			if m.PlanView != "" {
+				data.PlanView = m.PlanView
+				data.PlanChanges = formatPlanChanges(data.PlanStats)
+			}
+			if m.ShowResourceChanges || m.PlanView != "" {
+				data.ResourceChanges = result.PlanSuccess.ResourceChanges()
+				data.ShowResourceStats = m.ShowResourceStats
+				if m.SortResourceChangesBySeverity {
+					sortBySeverity(data.ResourceChanges)
+				}
+				limit := m.MaxResourceChanges
+				if limit <= 0 {
+					limit = defaultMaxResourceChanges
+				}
+				if len(data.ResourceChanges) > limit {
+					data.HiddenResourceChanges = data.ResourceChanges[limit:]
+					data.ResourceChanges = data.ResourceChanges[:limit]
+				}
+				if m.GroupResourceChangesByModule {
+					data.ModuleGroups = groupByModule(data.ResourceChanges, m.MaxModuleDepth)
+				}
+			}
+			if m.CollapseDiffContext {
+				diff := result.PlanSuccess.TerraformOutput
+				if common.EnableDiffMarkdownFormat {
+					diff = result.PlanSuccess.DiffMarkdownFormattedTerraformOutput()
+				}
+				if trimmed, ok := trimDiffContext(diff, m.DiffContextLines); ok {
+					data.TrimmedDiff = trimmed
+				}
+			}
+			if m.ShowApplySnippet {
+				commentBuilder := &CommentParser{ExecutableName: m.executableName}
+				data.ApplySnippet = commentBuilder.BuildApplyComment(result.RepoRelDir, result.Workspace, result.ProjectName, false)
+			}
+			if result.PlanSuccess.Superseded {
+				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("planSuperseded"), data)
+			} else if m.shouldUseWrappedTmpl(vcsHost, result.PlanSuccess.TerraformOutput) {
 				data.PlanSummary = result.PlanSuccess.Summary()
 				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("planSuccessWrapped"), data)
 			} else {
+				if result.PlanSuccess.DependenciesUnchanged && result.PlanSuccess.NoChanges() {
+					// The output only has the normal message for no changes.
+					data.PlanSummary = result.PlanSuccess.Summary()
+				}
 				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("planSuccessUnwrapped"), data)
 			}
 			resultData.NoChanges = result.PlanSuccess.NoChanges()
+			if m.ShowPlanDiffLineCount && result.PlanSuccess.TerraformOutput != "" {
+				resultData.DiffLines = strings.Count(result.PlanSuccess.TerraformOutput, "\n") + 1
+			}
 			numPlanSuccesses++
 		} else if result.PolicyCheckResults != nil && common.Command == policyCheckCommandTitle {
 			policyCheckResults := policyCheckResultsData{
@@ -249,14 +1360,26 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 				numPolicyApprovalSuccesses++
 			}
 		} else if result.ApplySuccess != "" {
-			output := strings.TrimSpace(result.ApplySuccess)
+			output := strings.TrimSpace(m.maskSecrets(result.ApplySuccess))
+			data := applySuccessData{
+				Output:         output,
+				Errors:         applyErrors(output),
+				ResourceCounts: result.ResourceCounts,
+				Downstream:     result.Downstream,
+			}
+			if m.CollapseProvisionerOutput {
+				data.Segments = splitProvisionerOutput(output)
+			}
 			if m.shouldUseWrappedTmpl(vcsHost, result.ApplySuccess) {
-				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("applyWrappedSuccess"), struct{ Output string }{output})
+				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("applyWrappedSuccess"), data)
 			} else {
-				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("applyUnwrappedSuccess"), struct{ Output string }{output})
+				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("applyUnwrappedSuccess"), data)
 			}
 		} else if result.VersionSuccess != "" {
 			output := strings.TrimSpace(result.VersionSuccess)
+			if match := reTerraformVersion.FindStringSubmatch(output); match != nil {
+				resultData.TerraformVersion = match[1]
+			}
 			if m.shouldUseWrappedTmpl(vcsHost, output) {
 				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("versionWrappedSuccess"), struct{ Output string }{output})
 			} else {
@@ -277,43 +1400,98 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 			} else {
 				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("stateRmSuccessUnwrapped"), result.StateRmSuccess)
 			}
+		} else if result.FmtCheckResult != nil {
+			resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("fmtCheck"), fmtCheckData{
+				FmtCheckResult: *result.FmtCheckResult,
+				RepoRelDir:     result.RepoRelDir,
+			})
+			fmtCheckRendered = true
+		} else if !(result.Error != nil || result.Failure != "") {
 			// Error out if no template was found, only if there are no errors or failures.
 			// This is because some errors and failures rely on additional context rendered by templtes, but not all errors or failures.
-		} else if !(result.Error != nil || result.Failure != "") {
-			resultData.Rendered = "Found no template. This is a bug!"
+			resultData.Rendered = m.noOutputMessage(cmdName, result)
+			noOutput = true
 		}
 		// Render error or failure templates. Done outside of previous block so that other context can be rendered for use here.
-		if result.Error != nil {
+		if result.CancellationReason != command.NotCancelled {
+			resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("cancelled"), cancelledData{
+				Reason:     result.CancellationReason,
+				commonData: common,
+			})
+		} else if result.Error != nil {
+			data := m.newErrData(result.Error, resultData.Rendered, common)
+			data.Wrapped = m.shouldUseWrappedTmpl(vcsHost, result.Error.Error())
 			tmpl := templates.Lookup("unwrappedErr")
-			if m.shouldUseWrappedTmpl(vcsHost, result.Error.Error()) {
+			if data.Phase == "init" {
+				tmpl = templates.Lookup("initErr")
+			} else if data.Wrapped {
 				tmpl = templates.Lookup("wrappedErr")
 			}
-			resultData.Rendered = m.renderTemplateTrimSpace(tmpl, errData{result.Error.Error(), resultData.Rendered, common})
+			resultData.Rendered = m.renderErr(tmpl, data)
 		} else if result.Failure != "" {
-			resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("failure"), failureData{result.Failure, resultData.Rendered, common})
+			resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("categorizedFailure"), failureData{
+				Failure:            result.Failure,
+				RenderedContext:    resultData.Rendered,
+				Category:           result.FailureCategory,
+				UnmetPrerequisites: result.UnmetPrerequisites,
+				commonData:         common,
+			})
+		} else if msg := m.SuccessMessages[cmdName]; msg != "" && !noOutput {
+			resultData.Rendered = msg + "\n\n" + resultData.Rendered
+		}
+		if result.FmtCheckResult != nil && len(result.FmtCheckResult.MisformattedFiles) > 0 && !fmtCheckRendered {
+			fmtCheck := m.renderTemplateTrimSpace(templates.Lookup("fmtCheck"), fmtCheckData{
+				FmtCheckResult: *result.FmtCheckResult,
+				RepoRelDir:     result.RepoRelDir,
+			})
+			resultData.Rendered = strings.TrimSpace(resultData.Rendered + "\n\n" + fmtCheck)
+		}
+		if m.isStalePlan(result) {
+			resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("stalePlan"), approxDuration(result.PlanAge)) + "\n\n" + resultData.Rendered
+		}
+		if result.ManualSteps != "" {
+			resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("manualSteps"), result) + "\n\n" + resultData.Rendered
+		}
+		if tmpl := m.projectTemplate(result.Labels); tmpl != nil {
+			resultData.Rendered = m.renderTemplateTrimSpace(tmpl, projectTemplateData{
+				ProjectResult: result,
+				Rendered:      resultData.Rendered,
+			})
 		}
 		resultsTmplData = append(resultsTmplData, resultData)
 	}
+	if common.Command == planCommandTitle && (common.HideUnchangedPlanComments || common.InlineNoChanges) {
+		for i := range resultsTmplData {
+			resultsTmplData[i].Hidden = resultsTmplData[i].NoChanges
+		}
+	}
+	m.markDuplicateProjects(resultsTmplData)
+	if m.GroupByOwner && len(resultsTmplData) > 1 {
+		markOwnerGroups(results, resultsTmplData, common.ReverseSectionOrder)
+	}
 
+	single := len(resultsTmplData) == 1 && !m.ForceMultiLayout
 	var tmpl *template.Template
 	switch {
-	case len(resultsTmplData) == 1 && common.Command == planCommandTitle && numPlanSuccesses > 0:
+	case len(resultsTmplData) == 0:
+		tmpl = templates.Lookup("noProjects")
+	case single && common.Command == planCommandTitle && numPlanSuccesses > 0:
 		tmpl = templates.Lookup("singleProjectPlanSuccess")
-	case len(resultsTmplData) == 1 && common.Command == planCommandTitle && numPlanSuccesses == 0:
+	case single && common.Command == planCommandTitle && numPlanSuccesses == 0:
 		tmpl = templates.Lookup("singleProjectPlanUnsuccessful")
-	case len(resultsTmplData) == 1 && common.Command == policyCheckCommandTitle && numPolicyCheckSuccesses > 0:
+	case single && common.Command == policyCheckCommandTitle && numPolicyCheckSuccesses > 0:
 		tmpl = templates.Lookup("singleProjectPlanSuccess")
-	case len(resultsTmplData) == 1 && common.Command == policyCheckCommandTitle && numPolicyCheckSuccesses == 0:
+	case single && common.Command == policyCheckCommandTitle && numPolicyCheckSuccesses == 0:
 		tmpl = templates.Lookup("singleProjectPolicyUnsuccessful")
-	case len(resultsTmplData) == 1 && common.Command == versionCommandTitle && numVersionSuccesses > 0:
+	case single && common.Command == versionCommandTitle && numVersionSuccesses > 0:
 		tmpl = templates.Lookup("singleProjectVersionSuccess")
-	case len(resultsTmplData) == 1 && common.Command == versionCommandTitle && numVersionSuccesses == 0:
+	case single && common.Command == versionCommandTitle && numVersionSuccesses == 0:
 		tmpl = templates.Lookup("singleProjectVersionUnsuccessful")
-	case len(resultsTmplData) == 1 && common.Command == applyCommandTitle:
+	case single && common.Command == applyCommandTitle:
 		tmpl = templates.Lookup("singleProjectApply")
-	case len(resultsTmplData) == 1 && common.Command == importCommandTitle:
+	case single && common.Command == importCommandTitle:
 		tmpl = templates.Lookup("singleProjectImport")
-	case len(resultsTmplData) == 1 && common.Command == stateCommandTitle:
+	case single && common.Command == stateCommandTitle:
 		switch common.SubCommand {
 		case "rm":
 			tmpl = templates.Lookup("singleProjectStateRm")
@@ -350,7 +1528,1209 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 	default:
 		return fmt.Sprintf("no template matched–this is a bug: command=%s", common.Command)
 	}
-	return m.renderTemplateTrimSpace(tmpl, resultData{resultsTmplData, common})
+	data := resultData{
+		Results:    resultsTmplData,
+		commonData: common,
+	}
+	if useDirectoryTable {
+		data.DirectoryTable = m.renderDirectoryTable(resultsTmplData)
+	}
+	if m.ShowStatusTable {
+		data.StatusTable = m.renderStatusTable(results)
+	}
+	return m.renderTemplateTrimSpace(tmpl, data)
+}
+
+// markDuplicateProjects adds a warning to the results for a project that
+// appears more than once, ex. because of a misconfigured repo config.
+// Projects are the same if they share a dir and workspace, whatever their
+// names. Results without a dir can't be told apart so they aren't duplicates.
+// The anchors of the duplicates after the first are numbered so that links
+// lead to each of them.
+func (m *MarkdownRenderer) markDuplicateProjects(results []projectResultTmplData) {
+	key := func(r projectResultTmplData) string {
+		return r.RepoRelDir + "\x00" + r.Workspace
+	}
+	counts := make(map[string]int)
+	anchors := make(map[string]int)
+	for i, r := range results {
+		counts[key(r)]++
+		if r.Anchor != "" {
+			anchors[r.Anchor]++
+			if n := anchors[r.Anchor]; n > 1 {
+				results[i].Anchor = fmt.Sprintf("%s-%d", r.Anchor, n)
+			}
+		}
+	}
+	for i, r := range results {
+		count := counts[key(r)]
+		if count < 2 || r.RepoRelDir == "" {
+			continue
+		}
+		warning := m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("duplicateProject"), duplicateProjectData{
+			RepoRelDir: r.RepoRelDir,
+			Workspace:  r.Workspace,
+			Count:      count,
+		})
+		results[i].Rendered = warning + "\n\n" + r.Rendered
+	}
+}
+
+// renderStatusTable renders a table with the status of each result and the
+// changes planned for it.
+func (m *MarkdownRenderer) renderStatusTable(results []command.ProjectResult) string {
+	buf := &bytes.Buffer{}
+	buf.WriteString("| Project | Workspace | Status | Changes |\n")
+	buf.WriteString("|---------|-----------|--------|---------|\n")
+	for _, r := range results {
+		project := r.ProjectName
+		if project == "" {
+			project = r.RepoRelDir
+		}
+		status := "Success"
+		if r.Error != nil {
+			status = "Error"
+		} else if isLockedFailure(r) {
+			status = "Locked"
+		} else if r.Failure != "" {
+			status = "Failed"
+		}
+		if m.StatusEmoji {
+			status = m.resultIcon(r) + " " + status
+		}
+		changes := "-"
+		if r.PlanSuccess != nil {
+			changes = formatPlanChanges(r.PlanSuccess.Stats())
+		}
+		fmt.Fprintf(buf, "| %s | %s | %s | %s |\n", codeSpan(project), codeSpan(r.Workspace), status, changes)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// renderDirectoryTable renders the directory list of a multi-project comment
+// as a table with DirectoryTableColumns columns. Each cell links to the
+// project's section, unless the section is hidden.
+func (m *MarkdownRenderer) renderDirectoryTable(results []projectResultTmplData) string {
+	columns := m.DirectoryTableColumns
+	if columns < 2 {
+		columns = 2
+	}
+	buf := &bytes.Buffer{}
+	buf.WriteString("|" + strings.Repeat("   |", columns) + "\n")
+	buf.WriteString("|" + strings.Repeat("---|", columns) + "\n")
+	for i := 0; i < len(results); i += columns {
+		buf.WriteString("|")
+		for j := i; j < i+columns; j++ {
+			if j >= len(results) {
+				buf.WriteString("   |")
+				continue
+			}
+			r := results[j]
+			label := fmt.Sprintf("`%s` (`%s`)", r.RepoRelDir, r.Workspace)
+			if r.ProjectName != "" {
+				label = fmt.Sprintf("`%s`", r.ProjectName)
+			}
+			if m.InlineNoChanges && r.NoChanges {
+				label += " — " + m.icon(SuccessIcon) + " no changes"
+			}
+			if r.Hidden {
+				fmt.Fprintf(buf, " %d. %s |", r.Num, label)
+			} else {
+				fmt.Fprintf(buf, " [%d. %s](#%s) |", r.Num, label, r.Anchor)
+			}
+		}
+		buf.WriteString("\n")
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// projectAnchor returns a stable HTML id for the section of the project in
+// dir and workspace.
+func projectAnchor(dir string, workspace string) string {
+	sanitize := func(s string) string {
+		s = strings.Trim(reAnchorUnsafe.ReplaceAllString(strings.ToLower(s), "-"), "-")
+		if s == "" {
+			return "root"
+		}
+		return s
+	}
+	return fmt.Sprintf("project-%s-%s", sanitize(dir), sanitize(workspace))
+}
+
+// noOutputMessage returns the message rendered for result when there is no
+// output to render for it, with enough context to diagnose why.
+func (m *MarkdownRenderer) noOutputMessage(cmdName command.Name, result command.ProjectResult) string {
+	msg := m.NoOutputMessage
+	if msg == "" {
+		msg = defaultNoOutputMessage
+	}
+	return fmt.Sprintf("%s (command: `%s`, dir: `%s`, workspace: `%s`)", msg, cmdName, result.RepoRelDir, result.Workspace)
+}
+
+// RenderLocks renders an overview of all the locks held by a pull request,
+// with links to release each of them.
+func (m *MarkdownRenderer) RenderLocks(locks []LockSummary) string {
+	return m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("locksOverview"), locksOverviewData{locks})
+}
+
+// RenderPlanComparison renders the resource changes that differ between a
+// previous plan and the current plan of the same project.
+func (m *MarkdownRenderer) RenderPlanComparison(previous models.PlanSuccess, current models.PlanSuccess) string {
+	prevChanges := previous.ResourceChanges()
+	curChanges := current.ResourceChanges()
+	data := planComparisonData{
+		Removed:       subtractResourceChanges(prevChanges, curChanges),
+		Added:         subtractResourceChanges(curChanges, prevChanges),
+		OutputChanged: strings.TrimSpace(previous.TerraformOutput) != strings.TrimSpace(current.TerraformOutput),
+	}
+	return m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("planComparison"), data)
+}
+
+// subtractResourceChanges returns the changes in a that aren't in b.
+func subtractResourceChanges(a []models.ResourceChange, b []models.ResourceChange) []models.ResourceChange {
+	key := func(change models.ResourceChange) string {
+		return change.Address + "\x00" + string(change.Action)
+	}
+	inB := make(map[string]bool)
+	for _, change := range b {
+		inB[key(change)] = true
+	}
+	var diff []models.ResourceChange
+	for _, change := range a {
+		if !inB[key(change)] {
+			diff = append(diff, change)
+		}
+	}
+	return diff
+}
+
+// RenderPromotion renders the status of promoting a change through envs, in
+// the order they're promoted in. Environments without results are pending,
+// or blocked if an earlier environment failed.
+func (m *MarkdownRenderer) RenderPromotion(cmdName command.Name, envs []EnvironmentResult) string {
+	data := promotionData{Command: cmdName.TitleString()}
+	failed := false
+	for _, env := range envs {
+		envData := promotionEnvData{
+			Name:     env.Name,
+			Projects: len(env.Results),
+			Changes:  "-",
+		}
+		res := command.Result{ProjectResults: env.Results}
+		switch {
+		case len(env.Results) == 0 && failed:
+			envData.Status = "⛔ Blocked"
+		case len(env.Results) == 0:
+			envData.Status = "⏸ Pending"
+		case res.HasErrors():
+			envData.Status = m.icon(FailureIcon) + " Failed"
+			failed = true
+		default:
+			envData.Status = m.icon(SuccessIcon) + " Succeeded"
+		}
+
+		var total models.PlanSuccessStats
+		for _, result := range env.Results {
+			if result.PlanSuccess == nil {
+				continue
+			}
+			stats := result.PlanSuccess.Stats()
+			total.Changes = total.Changes || stats.Changes
+			total.Import += stats.Import
+			total.Add += stats.Add
+			total.Change += stats.Change
+			total.Destroy += stats.Destroy
+			envData.Changes = formatPlanChanges(total)
+		}
+		data.Environments = append(data.Environments, envData)
+	}
+	return m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("promotion"), data)
+}
+
+// RenderPRSummary renders a matrix of the status of the latest plan, policy
+// check and apply of each project in a pull request. results are the results
+// of every command run for the pull request, oldest first, so later results
+// replace earlier ones for the same command and project. Projects are listed
+// in the order they first appear.
+func (m *MarkdownRenderer) RenderPRSummary(results []command.ProjectResult) string {
+	var data prSummaryData
+	index := make(map[string]int)
+	for _, result := range results {
+		key := strings.Join([]string{result.RepoRelDir, result.Workspace, result.ProjectName}, "\x00")
+		i, ok := index[key]
+		if !ok {
+			project := result.ProjectName
+			if project == "" {
+				project = result.RepoRelDir
+			}
+			i = len(data.Rows)
+			index[key] = i
+			data.Rows = append(data.Rows, prSummaryRow{
+				Project:     project,
+				Workspace:   result.Workspace,
+				Plan:        "-",
+				PolicyCheck: "-",
+				Apply:       "-",
+			})
+		}
+		switch result.Command {
+		case command.Plan:
+			data.Rows[i].Plan = m.prSummaryStatus(result)
+		case command.PolicyCheck, command.ApprovePolicies:
+			data.Rows[i].PolicyCheck = m.prSummaryStatus(result)
+		case command.Apply:
+			data.Rows[i].Apply = m.prSummaryStatus(result)
+		}
+	}
+	return m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("prSummary"), data)
+}
+
+// prSummaryStatus returns the status of result for RenderPRSummary, ex.
+// "✅ +1 ~0 -0" for a plan.
+func (m *MarkdownRenderer) prSummaryStatus(result command.ProjectResult) string {
+	status := "Succeeded"
+	switch {
+	case result.Error != nil:
+		status = "Error"
+	case isLockedFailure(result):
+		status = "Locked"
+	case result.Failure != "":
+		status = "Failed"
+	case result.PlanSuccess != nil:
+		status = formatPlanChanges(result.PlanSuccess.Stats())
+	case result.PolicyCheckResults != nil:
+		status = "Passed"
+	case result.ApplySuccess != "":
+		status = "Applied"
+	}
+	return m.resultIcon(result) + " " + status
+}
+
+// RenderChecks formats the data as the title, summary and text of a GitHub
+// check run. The summary and text are truncated to the limits of the Checks
+// API.
+func (m *MarkdownRenderer) RenderChecks(res command.Result, cmdName command.Name, subCmd, log string, verbose bool) ChecksPayload {
+	commandStr := cases.Title(language.English).String(strings.Replace(cmdName.String(), "_", " ", -1))
+	payload := ChecksPayload{
+		Title: checksTitle(res, commandStr),
+		Text:  m.renderBody(res, cmdName, subCmd, log, verbose, models.Github),
+	}
+	switch {
+	case res.Error != nil:
+		headline := errorHeadline(res.Error.Error())
+		if headline == "" {
+			headline = strings.TrimSpace(res.Error.Error())
+		}
+		payload.Summary = codeSpan(headline)
+	case res.Failure != "":
+		payload.Summary = res.Failure
+	case len(res.ProjectResults) > 0:
+		payload.Summary = m.renderStatusTable(res.ProjectResults)
+	}
+	for _, r := range res.ProjectResults {
+		if r.Error != nil {
+			payload.Annotations = append(payload.Annotations, ParseAnnotations(r.RepoRelDir, r.Error.Error())...)
+		}
+	}
+	footer := m.truncatedFooter()
+	for _, s := range []*string{&payload.Summary, &payload.Text} {
+		if len(*s) > maxChecksTextLength {
+			*s = truncateComment(*s, maxChecksTextLength-len(footer)-2, m.codeFence()) + "\n\n" + footer
+		}
+	}
+	return payload
+}
+
+// checksTitle returns a one line summary of res, ex. "Plan: 2 projects,
+// +1 ~2 -3".
+func checksTitle(res command.Result, commandStr string) string {
+	if res.Error != nil || res.Failure != "" {
+		return commandStr + " Failed"
+	}
+	if len(res.ProjectResults) == 0 {
+		return commandStr + ": No projects matched"
+	}
+	failed, locked := 0, 0
+	var total models.PlanSuccessStats
+	planned := false
+	for _, r := range res.ProjectResults {
+		if isLockedFailure(r) {
+			locked++
+		} else if r.Error != nil || r.Failure != "" {
+			failed++
+		}
+		if r.PlanSuccess != nil {
+			stats := r.PlanSuccess.Stats()
+			total.Import += stats.Import
+			total.Add += stats.Add
+			total.Change += stats.Change
+			total.Destroy += stats.Destroy
+			total.Changes = total.Changes || stats.Changes
+			planned = true
+		}
+	}
+	projects := "1 project"
+	if len(res.ProjectResults) > 1 {
+		projects = fmt.Sprintf("%d projects", len(res.ProjectResults))
+	}
+	switch {
+	case failed > 0 && locked > 0:
+		return fmt.Sprintf("%s: %d of %s failed, %d locked", commandStr, failed, projects, locked)
+	case failed > 0:
+		return fmt.Sprintf("%s: %d of %s failed", commandStr, failed, projects)
+	case locked > 0:
+		return fmt.Sprintf("%s: %d of %s locked", commandStr, locked, projects)
+	}
+	if planned {
+		return fmt.Sprintf("%s: %s, %s", commandStr, projects, formatPlanChanges(total))
+	}
+	return fmt.Sprintf("%s: %s succeeded", commandStr, projects)
+}
+
+// isLockedFailure returns true if result failed only because its project is
+// locked, which is retryable, rather than because of an error.
+func isLockedFailure(result command.ProjectResult) bool {
+	return result.Error == nil && result.Failure != "" && result.FailureCategory == command.LockedFailure
+}
+
+// formatPlanChanges formats the counts of changes in stats, ex. "+1 ~2 -3".
+func formatPlanChanges(stats models.PlanSuccessStats) string {
+	if !stats.Changes {
+		return "No changes"
+	}
+	changes := fmt.Sprintf("+%d ~%d -%d", stats.Add, stats.Change, stats.Destroy)
+	if stats.Import > 0 {
+		changes += fmt.Sprintf(", %d to import", stats.Import)
+	}
+	return changes
+}
+
+// RenderProgress renders the progress of a command that is still running on
+// total projects, of which completed are done, for comments updated as
+// projects complete. If elapsed is set, the time remaining is estimated from
+// it.
+func (m *MarkdownRenderer) RenderProgress(cmdName command.Name, completed int, total int, elapsed time.Duration) string {
+	if completed > total {
+		completed = total
+	}
+	data := progressData{
+		Verb:      progressVerb(cmdName),
+		Completed: completed,
+		Total:     total,
+		Bar:       strings.Repeat("█", progressBarWidth),
+		Percent:   100,
+	}
+	if total > 0 {
+		filled := completed * progressBarWidth / total
+		data.Bar = strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+		data.Percent = completed * 100 / total
+	}
+	if elapsed > 0 && completed > 0 && completed < total {
+		data.Remaining = approxDuration(elapsed / time.Duration(completed) * time.Duration(total-completed))
+	}
+	return m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("progress"), data)
+}
+
+// progressVerb returns the verb for cmdName running, ex. "Planning".
+func progressVerb(cmdName command.Name) string {
+	switch cmdName {
+	case command.Plan:
+		return "Planning"
+	case command.Apply:
+		return "Applying"
+	case command.PolicyCheck:
+		return "Checking policies"
+	case command.Import:
+		return "Importing"
+	default:
+		return "Running " + cmdName.String()
+	}
+}
+
+// changePercent formats the share of the total managed resources that are
+// changed or destroyed according to stats. Added resources aren't managed
+// yet, so they're left out.
+func changePercent(stats models.PlanSuccessStats, total int) string {
+	changed := stats.Change + stats.Destroy
+	switch {
+	case changed >= total:
+		return "100%"
+	case changed > 0 && changed*100 < total:
+		return "<1%"
+	default:
+		return fmt.Sprintf("%d%%", int(math.Round(float64(changed)*100/float64(total))))
+	}
+}
+
+// RenderQueueStatus renders the position of a pull request in the apply
+// queue, where position 1 is next, along with the pull requests ahead of it.
+// A position less than 1 means the queue is empty.
+func (m *MarkdownRenderer) RenderQueueStatus(position int, ahead []models.PullRequest) string {
+	return m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("queueStatus"), queueStatusData{
+		Position: position,
+		Ahead:    ahead,
+	})
+}
+
+// displayDir returns dir relative to ProjectPathRoot, or an empty string if
+// ProjectPathRoot isn't set or dir isn't under it.
+func (m *MarkdownRenderer) displayDir(dir string) string {
+	root := strings.TrimSuffix(m.ProjectPathRoot, "/")
+	if root == "" || root == "." {
+		return ""
+	}
+	if rel := strings.TrimPrefix(dir, root+"/"); rel != dir && rel != "" {
+		return rel
+	}
+	return ""
+}
+
+// maskSecrets replaces everything in output matching one of
+// SecretMaskPatterns with "***".
+func (m *MarkdownRenderer) maskSecrets(output string) string {
+	return maskSecrets(output, m.SecretMaskPatterns)
+}
+
+// linkifyTickets links the ticket references matching TicketLinks in
+// comment. Code blocks, code spans, links and HTML tags are left untouched.
+func (m *MarkdownRenderer) linkifyTickets(comment string) string {
+	fence := m.codeFence()
+	for _, link := range m.TicketLinks {
+		lines := strings.Split(comment, "\n")
+		inCodeBlock := false
+		for i, line := range lines {
+			trimmed := strings.TrimSpace(line)
+			if inCodeBlock {
+				if strings.HasPrefix(trimmed, fence) {
+					inCodeBlock = false
+				}
+				continue
+			}
+			if strings.HasPrefix(trimmed, fence) {
+				inCodeBlock = true
+				continue
+			}
+			var linked strings.Builder
+			last := 0
+			for _, span := range reUnlinkable.FindAllStringIndex(line, -1) {
+				linked.WriteString(link.replace(line[last:span[0]]))
+				linked.WriteString(line[span[0]:span[1]])
+				last = span[1]
+			}
+			linked.WriteString(link.replace(line[last:]))
+			lines[i] = linked.String()
+		}
+		comment = strings.Join(lines, "\n")
+	}
+	return comment
+}
+
+// replace replaces the ticket references in text with links.
+func (l TicketLink) replace(text string) string {
+	return l.Pattern.ReplaceAllStringFunc(text, func(ref string) string {
+		submatches := l.Pattern.FindStringSubmatchIndex(ref)
+		return fmt.Sprintf("[%s](%s)", ref, l.Pattern.ExpandString(nil, l.URL, ref, submatches))
+	})
+}
+
+// normalizeNewlines converts CRLF and CR line endings in s to LF.
+func normalizeNewlines(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\r", "\n")
+}
+
+// copyResultOutputs replaces the outputs result points to with copies, so
+// that they can be rewritten without changing the original result.
+func copyResultOutputs(result *command.ProjectResult) {
+	if result.PlanSuccess != nil {
+		planSuccess := *result.PlanSuccess
+		result.PlanSuccess = &planSuccess
+	}
+	if result.PolicyCheckResults != nil {
+		policyCheckResults := *result.PolicyCheckResults
+		result.PolicyCheckResults = &policyCheckResults
+	}
+	if result.ImportSuccess != nil {
+		importSuccess := *result.ImportSuccess
+		result.ImportSuccess = &importSuccess
+	}
+	if result.StateRmSuccess != nil {
+		stateRmSuccess := *result.StateRmSuccess
+		result.StateRmSuccess = &stateRmSuccess
+	}
+}
+
+// normalizeResultNewlines converts the line endings of the output in result
+// to LF.
+func normalizeResultNewlines(result *command.ProjectResult) {
+	if result.PlanSuccess != nil {
+		result.PlanSuccess.TerraformOutput = normalizeNewlines(result.PlanSuccess.TerraformOutput)
+	}
+	if result.PolicyCheckResults != nil {
+		result.PolicyCheckResults.PreConftestOutput = normalizeNewlines(result.PolicyCheckResults.PreConftestOutput)
+		result.PolicyCheckResults.PostConftestOutput = normalizeNewlines(result.PolicyCheckResults.PostConftestOutput)
+	}
+	if result.ImportSuccess != nil {
+		result.ImportSuccess.Output = normalizeNewlines(result.ImportSuccess.Output)
+	}
+	if result.StateRmSuccess != nil {
+		result.StateRmSuccess.Output = normalizeNewlines(result.StateRmSuccess.Output)
+	}
+	result.ApplySuccess = normalizeNewlines(result.ApplySuccess)
+	result.VersionSuccess = normalizeNewlines(result.VersionSuccess)
+	result.Failure = normalizeNewlines(result.Failure)
+}
+
+// renderErr renders data with tmpl, followed by the link to its known issue
+// if it has one.
+func (m *MarkdownRenderer) renderErr(tmpl *template.Template, data errData) string {
+	rendered := m.renderTemplateTrimSpace(tmpl, data)
+	if data.KnownIssueURL != "" {
+		rendered += "\n\n" + m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("knownIssue"), data)
+	}
+	return rendered
+}
+
+// newErrData builds the template data for err, including its exit code if
+// it carries one.
+func (m *MarkdownRenderer) newErrData(err error, renderedContext string, common commonData) errData {
+	msg := err.Error()
+	if !m.DisableNewlineNormalization {
+		msg = normalizeNewlines(msg)
+	}
+	data := errData{
+		Error:           msg,
+		RenderedContext: renderedContext,
+		commonData:      common,
+	}
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		data.ExitCode = exitErr.ExitCode()
+	}
+	var phaseErr command.PhaseError
+	if errors.As(err, &phaseErr) {
+		data.Phase = phaseErr.Phase
+	}
+	for _, issue := range m.KnownIssues {
+		if issue.Pattern.MatchString(data.Error) {
+			data.KnownIssueURL = issue.URL
+			break
+		}
+	}
+	data.ChecksumMismatch = reChecksumMismatch.MatchString(data.Error)
+	data.BackendMigration = reBackendMigration.MatchString(data.Error)
+	for _, match := range rePreventDestroy.FindAllStringSubmatch(data.Error, -1) {
+		data.ProtectedResources = append(data.ProtectedResources, match[1])
+	}
+	// A checksum mismatch and a backend migration have their own
+	// remediation, even though they also fail installing providers or
+	// initializing the backend.
+	if data.Phase == "init" && !data.ChecksumMismatch && !data.BackendMigration {
+		switch {
+		case models.NeedsInitUpgrade(data.Error):
+			data.InitProblem = "upgrade"
+		case reInitBackendProblem.MatchString(data.Error):
+			data.InitProblem = "backend"
+		case reInitProviderProblem.MatchString(data.Error):
+			data.InitProblem = "provider"
+		}
+	}
+	return data
+}
+
+// errorHeadline returns the first line of msg that reports an error, or else
+// its first non-empty line, if msg has multiple lines. It returns an empty
+// string for single-line errors.
+func errorHeadline(msg string) string {
+	msg = strings.TrimSpace(msg)
+	if !strings.Contains(msg, "\n") {
+		return ""
+	}
+	first := ""
+	for _, line := range strings.Split(msg, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(line, "│╷╵ \t"))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "Error: ") {
+			return line
+		}
+		if first == "" {
+			first = line
+		}
+	}
+	return first
+}
+
+// renderMinimal renders the overall status of the command and a one-line
+// summary for each project.
+func (m *MarkdownRenderer) renderMinimal(res command.Result, common commonData) string {
+	data := minimalData{commonData: common}
+	if res.Error != nil {
+		data.Error = res.Error.Error()
+	}
+	data.Failure = res.Failure
+	for _, result := range res.ProjectResults {
+		resultData := minimalResultData{
+			projectResultTmplData: projectResultTmplData{
+				Workspace:   result.Workspace,
+				RepoRelDir:  result.RepoRelDir,
+				ProjectName: result.ProjectName,
+				DisplayDir:  m.displayDir(result.RepoRelDir),
+			},
+			Succeeded: result.Error == nil && result.Failure == "",
+			Icon:      m.resultIcon(result),
+		}
+		switch {
+		case result.Error != nil:
+			resultData.Summary = "Error"
+		case result.Failure != "":
+			resultData.Summary = "Failed: " + result.Failure
+		case result.PlanSuccess != nil:
+			resultData.Summary = result.PlanSuccess.DiffSummary()
+		case result.ApplySuccess != "":
+			resultData.Summary = reApplySummary.FindString(result.ApplySuccess)
+		case result.PolicyCheckResults != nil:
+			resultData.Summary = strings.Replace(result.PolicyCheckResults.Summary(), "\n", "; ", -1)
+		}
+		if resultData.Succeeded {
+			data.NumSuccesses++
+		} else if isLockedFailure(result) {
+			data.NumLocked++
+		}
+		data.Results = append(data.Results, resultData)
+	}
+	return m.renderTemplateTrimSpace(m.markdownTemplates.Lookup("minimal"), data)
+}
+
+// splitProvisionerOutput splits output into runs of provisioner output and
+// the rest. It returns nil if output has no provisioner output.
+func splitProvisionerOutput(output string) []outputSegment {
+	if !reProvisionerLine.MatchString(output) {
+		return nil
+	}
+	var segments []outputSegment
+	for _, line := range strings.Split(output, "\n") {
+		provisioner := reProvisionerLine.MatchString(line)
+		if n := len(segments); n > 0 && segments[n-1].Provisioner == provisioner {
+			segments[n-1].Output += "\n" + line
+			segments[n-1].Lines++
+			continue
+		}
+		segments = append(segments, outputSegment{Output: line, Provisioner: provisioner, Lines: 1})
+	}
+	for i := range segments {
+		segments[i].Output = strings.Trim(segments[i].Output, "\n")
+	}
+	return segments
+}
+
+// applyErrors returns the error lines in the output of an apply.
+func applyErrors(output string) []string {
+	var errs []string
+	for _, match := range reApplyError.FindAllStringSubmatch(output, -1) {
+		errs = append(errs, match[1])
+	}
+	return errs
+}
+
+// approxDuration formats d rounded to the minute, or to the second if it's
+// less than a minute, ex. "2m" or "1h5m".
+func approxDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Round(time.Second).Seconds()))
+	}
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	}
+}
+
+// allPlansSuperseded returns true if res has plans and all of them were
+// superseded.
+func allPlansSuperseded(res command.Result) bool {
+	superseded := false
+	for _, result := range res.ProjectResults {
+		if result.PlanSuccess == nil {
+			continue
+		}
+		if !result.PlanSuccess.Superseded {
+			return false
+		}
+		superseded = true
+	}
+	return superseded
+}
+
+// unassignedOwner is the group of projects without an Owner.
+const unassignedOwner = "Unassigned"
+
+// sortByOwner returns a copy of results grouped by Owner, in the order each
+// owner first appears, with unassigned projects last.
+func sortByOwner(results []command.ProjectResult) []command.ProjectResult {
+	order := make(map[string]int)
+	for _, result := range results {
+		if _, ok := order[result.Owner]; !ok && result.Owner != "" {
+			order[result.Owner] = len(order)
+		}
+	}
+	order[""] = len(order)
+	sorted := make([]command.ProjectResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return order[sorted[i].Owner] < order[sorted[j].Owner]
+	})
+	return sorted
+}
+
+// markOwnerGroups sets the Team heading of the first section rendered for
+// each owner in results. If reversed, sections are rendered in reverse
+// order. Hidden sections are skipped so that each heading is on a section
+// that is rendered.
+func markOwnerGroups(results []command.ProjectResult, data []projectResultTmplData, reversed bool) {
+	prev := "\x00"
+	for n := range data {
+		i := n
+		if reversed {
+			i = len(data) - 1 - n
+		}
+		if data[i].Hidden || results[i].Owner == prev {
+			continue
+		}
+		prev = results[i].Owner
+		data[i].Team = prev
+		if data[i].Team == "" {
+			data[i].Team = unassignedOwner
+		}
+	}
+}
+
+// isStalePlan returns true if result succeeded using a plan older than
+// StalePlanAge.
+func (m *MarkdownRenderer) isStalePlan(result command.ProjectResult) bool {
+	if result.Error != nil || result.Failure != "" {
+		return false
+	}
+	threshold := m.StalePlanAge
+	if threshold <= 0 {
+		threshold = defaultStalePlanAge
+	}
+	return result.PlanAge > threshold
+}
+
+// capWarnings splits warnings into the first limit and the rest.
+func capWarnings(warnings []string, limit int) ([]string, []string) {
+	if len(warnings) <= limit {
+		return warnings, nil
+	}
+	return warnings[:limit], warnings[limit:]
+}
+
+// actionSeverities ranks resource actions by how risky they are, lowest
+// first. Actions that aren't ranked, ex. reads, sort last.
+var actionSeverities = map[models.ResourceAction]int{
+	models.DeleteResourceAction:  1,
+	models.ReplaceResourceAction: 2,
+	models.UpdateResourceAction:  3,
+	models.CreateResourceAction:  4,
+}
+
+// sortBySeverity sorts changes by the severity of their action, keeping
+// changes with the same action in address order.
+func sortBySeverity(changes []models.ResourceChange) {
+	rank := func(action models.ResourceAction) int {
+		if severity, ok := actionSeverities[action]; ok {
+			return severity
+		}
+		return len(actionSeverities) + 1
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return rank(changes[i].Action) < rank(changes[j].Action)
+	})
+}
+
+// moduleGroup is the changes to the resources in a single module. Module is
+// empty for the root module.
+type moduleGroup struct {
+	Module  string
+	Changes []models.ResourceChange
+}
+
+// groupByModule groups changes by the module in their address, keeping the
+// order in which each module first appears. Modules nested deeper than
+// maxDepth are grouped under their ancestor at maxDepth, unless maxDepth is
+// 0.
+func groupByModule(changes []models.ResourceChange, maxDepth int) []moduleGroup {
+	var groups []moduleGroup
+	index := make(map[string]int)
+	for _, change := range changes {
+		modules := reModuleAddress.FindAllString(reModulePrefix.FindString(change.Address), -1)
+		if maxDepth > 0 && len(modules) > maxDepth {
+			modules = modules[:maxDepth]
+		}
+		module := strings.Join(modules, ".")
+		i, ok := index[module]
+		if !ok {
+			i = len(groups)
+			index[module] = i
+			groups = append(groups, moduleGroup{Module: module})
+		}
+		groups[i].Changes = append(groups[i].Changes, change)
+	}
+	return groups
+}
+
+// shortenModuleSources replaces git module sources in output with a compact
+// form of their host, path, subdirectory and ref.
+func shortenModuleSources(output string) string {
+	return reModuleSource.ReplaceAllStringFunc(output, func(source string) string {
+		m := reModuleSource.FindStringSubmatch(source)
+		if m[1] == "" && m[4] == "" {
+			return source
+		}
+		short := m[2] + "/" + m[3] + m[5]
+		if m[6] != "" {
+			short += "@" + m[6]
+		}
+		// Keep the quote or whitespace that ended the source.
+		return short + m[7]
+	})
+}
+
+// collapseComputedAttributes replaces each run of consecutive attributes
+// whose value is "(known after apply)" in output with a single line counting
+// them.
+func collapseComputedAttributes(output string) string {
+	lines := strings.Split(output, "\n")
+	var collapsed []string
+	for i := 0; i < len(lines); {
+		match := reComputedAttribute.FindStringSubmatch(lines[i])
+		j := i
+		for j < len(lines) && reComputedAttribute.MatchString(lines[j]) {
+			j++
+		}
+		if j-i < 2 {
+			collapsed = append(collapsed, lines[i])
+			i++
+			continue
+		}
+		collapsed = append(collapsed, fmt.Sprintf("%s...%d computed attributes...", match[1], j-i))
+		i = j
+	}
+	return strings.Join(collapsed, "\n")
+}
+
+// collapseDataSourceReads removes the lines in output about reading data
+// sources and puts a single line counting the data sources read where the
+// first one was.
+func collapseDataSourceReads(output string) string {
+	lines := strings.Split(output, "\n")
+	var collapsed []string
+	first := -1
+	read := make(map[string]bool)
+	for _, line := range lines {
+		match := reDataSourceRead.FindStringSubmatch(line)
+		if match == nil {
+			collapsed = append(collapsed, line)
+			continue
+		}
+		if first < 0 {
+			first = len(collapsed)
+			collapsed = append(collapsed, "")
+		}
+		read[match[1]] = true
+	}
+	if first < 0 {
+		return output
+	}
+	noun := "data sources"
+	if len(read) == 1 {
+		noun = "data source"
+	}
+	collapsed[first] = fmt.Sprintf("...read %d %s...", len(read), noun)
+	return strings.Join(collapsed, "\n")
+}
+
+// extractJSONValues replaces the long string attributes in output whose
+// value is a JSON object or array with a short placeholder, and returns the
+// values pretty-printed. Values that don't unquote or parse exactly are left
+// alone so that nothing else is changed.
+func extractJSONValues(output string) (string, []jsonValue) {
+	lines := strings.Split(output, "\n")
+	var values []jsonValue
+	for i, line := range lines {
+		match := reJSONAttribute.FindStringSubmatch(line)
+		if match == nil || len(match[3]) < minJSONValueLength {
+			continue
+		}
+		raw, err := strconv.Unquote(match[3])
+		if err != nil || !json.Valid([]byte(raw)) {
+			continue
+		}
+		buf := &bytes.Buffer{}
+		if err := json.Indent(buf, []byte(raw), "", "  "); err != nil {
+			continue
+		}
+		values = append(values, jsonValue{Attribute: match[2], JSON: buf.String()})
+		lines[i] = fmt.Sprintf("%s(JSON %d, shown below)", match[1], len(values))
+	}
+	return strings.Join(lines, "\n"), values
+}
+
+// trimDiffContext keeps the changed lines of diff and up to n lines of
+// context around each, replacing the rest with a marker like git's hunk
+// headers. It returns false if nothing was trimmed.
+func trimDiffContext(diff string, n int) (string, bool) {
+	if n < 0 {
+		n = 0
+	}
+	lines := strings.Split(diff, "\n")
+	keep := make([]bool, len(lines))
+	for i, line := range lines {
+		if !reDiffChangedLine.MatchString(line) {
+			continue
+		}
+		for j := i - n; j <= i+n; j++ {
+			if j >= 0 && j < len(lines) {
+				keep[j] = true
+			}
+		}
+	}
+
+	var trimmed []string
+	hidden := 0
+	hiddenMarker := func() string {
+		if hidden == 1 {
+			return "@@ 1 unchanged line hidden @@"
+		}
+		return fmt.Sprintf("@@ %d unchanged lines hidden @@", hidden)
+	}
+	for i, line := range lines {
+		if keep[i] {
+			if hidden > 0 {
+				trimmed = append(trimmed, hiddenMarker())
+				hidden = 0
+			}
+			trimmed = append(trimmed, line)
+			continue
+		}
+		hidden++
+	}
+	if hidden == len(lines) {
+		return "", false
+	}
+	if hidden > 0 {
+		trimmed = append(trimmed, hiddenMarker())
+	}
+	if len(trimmed) >= len(lines) {
+		return "", false
+	}
+	return strings.Join(trimmed, "\n"), true
+}
+
+// flattenFolds replaces collapsible sections that are nested deeper than
+// maxDepth with a bold title followed by their content. Code blocks, fenced
+// with fence, are left untouched.
+func flattenFolds(comment string, maxDepth int, fence string) string {
+	lines := strings.Split(comment, "\n")
+	// flattened holds, for each open collapsible section, whether it was
+	// flattened so that its closing tag can be removed too.
+	var flattened []bool
+	inCodeBlock := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if inCodeBlock {
+			if strings.HasPrefix(trimmed, fence) {
+				inCodeBlock = false
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, fence) {
+			inCodeBlock = true
+			continue
+		}
+		lines[i] = reFoldTag.ReplaceAllStringFunc(line, func(tag string) string {
+			if tag == "</details>" {
+				if len(flattened) == 0 {
+					return tag
+				}
+				flat := flattened[len(flattened)-1]
+				flattened = flattened[:len(flattened)-1]
+				if flat {
+					return ""
+				}
+				return tag
+			}
+			flat := len(flattened) >= maxDepth
+			flattened = append(flattened, flat)
+			if !flat {
+				return tag
+			}
+			if summary := reFoldTag.FindStringSubmatch(tag)[1]; summary != "" {
+				return "**" + summary + "**"
+			}
+			return ""
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+// printFriendly expands every collapsible section in comment and removes
+// the elements that only make sense in a pull request: the instructions to
+// comment a command or click a link, anchors and task list checkboxes. Code
+// blocks, fenced with fence, are kept as is.
+func printFriendly(comment string, fence string) string {
+	comment = flattenFolds(comment, 0, fence)
+	comment = reAnchor.ReplaceAllString(comment, "")
+	lines := strings.Split(comment, "\n")
+	var kept []string
+	inCodeBlock := false
+	// actionIndent is the indentation of the action being removed, or -1
+	// if none is, so that its nested items are removed too.
+	actionIndent := -1
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if actionIndent >= 0 {
+			if trimmed != "" && indent > actionIndent {
+				continue
+			}
+			actionIndent = -1
+		}
+		if inCodeBlock {
+			if strings.HasPrefix(trimmed, fence) {
+				inCodeBlock = false
+			}
+			kept = append(kept, line)
+			continue
+		}
+		if strings.HasPrefix(trimmed, fence) {
+			inCodeBlock = true
+			kept = append(kept, line)
+			continue
+		}
+		if reActionItem.MatchString(line) {
+			actionIndent = indent
+			continue
+		}
+		// Squash the blank lines and separators left by removed actions.
+		if trimmed == "" && len(kept) > 0 && strings.TrimSpace(kept[len(kept)-1]) == "" {
+			continue
+		}
+		if trimmed == "---" && lastNonBlank(kept) == "---" {
+			continue
+		}
+		kept = append(kept, reTaskListItem.ReplaceAllString(line, "$1"))
+	}
+	return strings.TrimSuffix(strings.TrimRight(strings.Join(kept, "\n"), "\n"), "\n---")
+}
+
+// lastNonBlank returns the last line in lines that isn't blank, trimmed.
+func lastNonBlank(lines []string) string {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if trimmed := strings.TrimSpace(lines[i]); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+// rightToLeftLanguages are the base languages written right to left.
+var rightToLeftLanguages = map[string]bool{
+	"ar": true, "ckb": true, "dv": true, "fa": true, "he": true,
+	"ps": true, "sd": true, "ug": true, "ur": true, "yi": true,
+}
+
+// isRightToLeft returns true if locale is a right-to-left language.
+func isRightToLeft(locale string) bool {
+	if locale == "" {
+		return false
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return false
+	}
+	base, _ := tag.Base()
+	return rightToLeftLanguages[base.String()]
+}
+
+// rightToLeft renders comment right to left, except for its code blocks,
+// fenced with fence, which are kept left to right.
+func rightToLeft(comment string, fence string) string {
+	var lines []string
+	inCodeBlock := false
+	for _, line := range strings.Split(comment, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case inCodeBlock && strings.HasPrefix(trimmed, fence):
+			inCodeBlock = false
+			lines = append(lines, line, "", "</div>")
+		case inCodeBlock:
+			lines = append(lines, line)
+		case strings.HasPrefix(trimmed, fence):
+			inCodeBlock = true
+			lines = append(lines, `<div dir="ltr">`, "", line)
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return "<div dir=\"rtl\">\n\n" + strings.Join(lines, "\n") + "\n\n</div>"
+}
+
+// truncateComment cuts comment at a line boundary so that it's at most limit
+// long, closing any code block, fenced with fence, collapsible section or div
+// left open by the cut.
+func truncateComment(comment string, limit int, fence string) string {
+	var kept []string
+	length := 0
+	inCodeBlock := false
+	// openTags are the names of the tags left open, innermost last.
+	var openTags []string
+	for _, line := range strings.Split(comment, "\n") {
+		lineInCodeBlock, lineOpenTags := inCodeBlock, openTags
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, fence) {
+			lineInCodeBlock = !lineInCodeBlock
+		} else if !lineInCodeBlock {
+			lineOpenTags = append([]string(nil), openTags...)
+			for _, tag := range reBlockTag.FindAllStringSubmatch(line, -1) {
+				if tag[1] == "" {
+					lineOpenTags = append(lineOpenTags, tag[2])
+				} else if n := len(lineOpenTags); n > 0 && lineOpenTags[n-1] == tag[2] {
+					lineOpenTags = lineOpenTags[:n-1]
+				}
+			}
+		}
+
+		closersLen := 0
+		for _, tag := range lineOpenTags {
+			closersLen += len("\n</" + tag + ">")
+		}
+		if lineInCodeBlock {
+			closersLen += len(fence) + 1
+		}
+		if length+len(line)+1+closersLen > limit {
+			break
+		}
+		kept = append(kept, line)
+		length += len(line) + 1
+		inCodeBlock, openTags = lineInCodeBlock, lineOpenTags
+	}
+
+	truncated := strings.TrimRight(strings.Join(kept, "\n"), "\n")
+	if inCodeBlock {
+		truncated += "\n" + fence
+	}
+	for i := len(openTags) - 1; i >= 0; i-- {
+		truncated += "\n</" + openTags[i] + ">"
+	}
+	return truncated
 }
 
 // shouldUseWrappedTmpl returns true if we should use the wrapped markdown
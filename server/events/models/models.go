@@ -357,6 +357,9 @@ type PlanSuccess struct {
 	TerraformOutput string
 	// LockURL is the full URL to the lock held by this plan.
 	LockURL string
+	// LockURLs are the full URLs to the locks held by this plan, if it holds
+	// more than the one at LockURL, ex. across several workspaces.
+	LockURLs []string
 	// RePlanCmd is the command that users should run to re-plan this project.
 	RePlanCmd string
 	// ApplyCmd is the command that users should run to apply this plan.
@@ -365,6 +368,35 @@ type PlanSuccess struct {
 	// branch we're merging into had been updated, and we had to merge again
 	// before planning
 	MergedAgain bool
+	// RefreshOnly is true if the plan was run with -refresh-only, in which
+	// case the output describes drift rather than desired changes.
+	RefreshOnly bool
+	// DriftedResources are the addresses of resources that were changed
+	// outside of Terraform.
+	DriftedResources []string
+	// EstApplyDuration is how long applying the plan is expected to take,
+	// ex. based on previous applies of the project. It is 0 if unknown.
+	EstApplyDuration time.Duration
+	// DependenciesUnchanged is true if the dependency lock file is unchanged
+	// as well, so that a plan without changes is a complete no-op.
+	DependenciesUnchanged bool
+	// RequiredApprovers are the users or teams that must approve the plan
+	// before it can be applied, ex. "@org/team-x" from CODEOWNERS.
+	RequiredApprovers []string
+	// AutoApply is true if the plan will be applied without a manual apply,
+	// ex. by an automated workflow.
+	AutoApply bool
+	// Superseded is true if a newer plan replaced this one, so that
+	// comments rendered for it no longer offer to apply it.
+	Superseded bool
+	// ManagedResources is the number of resources in the state before the
+	// plan, or 0 if unknown.
+	ManagedResources int
+	// RequiredApprovals is the number of approvals the pull request needs
+	// before the plan can be applied, or 0 if none are required.
+	// CurrentApprovals is the number it has.
+	RequiredApprovals int
+	CurrentApprovals  int
 }
 
 type PolicySetResult struct {
@@ -373,6 +405,9 @@ type PolicySetResult struct {
 	Passed         bool
 	ReqApprovals   int
 	CurApprovals   int
+	// PolicySetSource is where the policy set's policies are loaded from,
+	// ex. a local path or a URL. It is empty if unknown.
+	PolicySetSource string `json:",omitempty"`
 }
 
 // PolicySetApproval tracks the number of approvals a given policy set has.
@@ -391,6 +426,12 @@ var (
 
 // Summary extracts summaries of plan changes from TerraformOutput.
 func (p *PlanSuccess) Summary() string {
+	if p.RefreshOnly {
+		if reChangesOutside.MatchString(p.TerraformOutput) {
+			return "**Drift detected:** applying this plan will update the state to match the remote objects without changing them."
+		}
+		return "No drift detected."
+	}
 	note := ""
 	if match := reChangesOutside.FindString(p.TerraformOutput); match != "" {
 		note = "\n**" + match + "**\n"
@@ -403,6 +444,9 @@ func (p *PlanSuccess) DiffSummary() string {
 	if match := rePlanChanges.FindString(p.TerraformOutput); match != "" {
 		return match
 	}
+	if p.DependenciesUnchanged && p.NoChanges() {
+		return "No changes and dependencies unchanged."
+	}
 	return reNoChanges.FindString(p.TerraformOutput)
 }
 
@@ -411,6 +455,328 @@ func (p *PlanSuccess) NoChanges() bool {
 	return reNoChanges.MatchString(p.TerraformOutput)
 }
 
+// ApprovalsNeeded returns the number of approvals still needed before the
+// plan can be applied.
+func (p *PlanSuccess) ApprovalsNeeded() int {
+	if p.CurrentApprovals >= p.RequiredApprovals {
+		return 0
+	}
+	return p.RequiredApprovals - p.CurrentApprovals
+}
+
+// reDeprecation matches deprecation notices in Terraform output, optionally
+// prefixed by the box-drawing characters Terraform uses for diagnostics.
+var reDeprecation = regexp.MustCompile(`(?m)^[\s│]*(Deprecated: .*|Warning: .*[Dd]eprecated.*)$`)
+
+// Deprecations extracts deprecation notices from TerraformOutput.
+func (p *PlanSuccess) Deprecations() []string {
+	var deprecations []string
+	for _, m := range reDeprecation.FindAllStringSubmatch(p.TerraformOutput, -1) {
+		deprecations = append(deprecations, strings.TrimSpace(m[1]))
+	}
+	return deprecations
+}
+
+// reAuthNotice matches the warnings providers print about authentication,
+// ex. expiring credentials or assumed roles, optionally prefixed by the
+// box-drawing characters Terraform uses for diagnostics.
+var reAuthNotice = regexp.MustCompile(`(?m)^[\s│]*(Warning: .*(?i:credential|access token|assume[ds]? role|assuming role|expir|authenticat|session token|az login|gcloud auth|sso (?:session|token)).*)$`)
+
+// AuthNotices extracts provider authentication warnings from
+// TerraformOutput.
+func (p *PlanSuccess) AuthNotices() []string {
+	var notices []string
+	for _, m := range reAuthNotice.FindAllStringSubmatch(p.TerraformOutput, -1) {
+		notices = append(notices, strings.TrimSpace(m[1]))
+	}
+	return notices
+}
+
+// reInitUpgradeHint matches Terraform's suggestion to upgrade providers or
+// modules, ex. 'To upgrade to newer versions, run "terraform init -upgrade"'.
+var reInitUpgradeHint = regexp.MustCompile(`\b(?:terraform|tofu) init -upgrade\b`)
+
+// NeedsInitUpgrade returns true if the output of a Terraform command suggests
+// running 'terraform init -upgrade'.
+func NeedsInitUpgrade(output string) bool {
+	return reInitUpgradeHint.MatchString(output)
+}
+
+// reDriftedResource matches the resources Terraform lists under
+// "Objects have changed outside of Terraform".
+var reDriftedResource = regexp.MustCompile(`(?m)^\s*# (\S+) has (?:changed|been deleted)$`)
+
+// ParseDriftedResources extracts the addresses of resources that were changed
+// outside of Terraform from the output of a plan.
+func ParseDriftedResources(output string) []string {
+	var addresses []string
+	for _, m := range reDriftedResource.FindAllStringSubmatch(output, -1) {
+		addresses = append(addresses, m[1])
+	}
+	return addresses
+}
+
+// reWorkspaceNotice matches the notices Terraform prints when selecting or
+// creating a workspace.
+var reWorkspaceNotice = regexp.MustCompile(`(?m)^(Created and switched to workspace "[^"]*"!|Switched to workspace "[^"]*"\.)`)
+
+// WorkspaceNotices extracts workspace selection notices from TerraformOutput.
+func (p *PlanSuccess) WorkspaceNotices() []string {
+	return reWorkspaceNotice.FindAllString(p.TerraformOutput, -1)
+}
+
+// reProvider matches the providers Terraform reports when initializing.
+var reProvider = regexp.MustCompile(`(?m)^- (Installed|Using previously-installed) (\S+) v(\S+)`)
+
+// ProviderVersion is a provider used by a plan.
+type ProviderVersion struct {
+	Name    string
+	Version string
+	// Installed is true if the provider was newly installed rather than
+	// already present.
+	Installed bool
+}
+
+// Providers extracts the providers initialized for the plan from
+// TerraformOutput.
+func (p *PlanSuccess) Providers() []ProviderVersion {
+	var providers []ProviderVersion
+	seen := make(map[string]bool)
+	for _, m := range reProvider.FindAllStringSubmatch(p.TerraformOutput, -1) {
+		if seen[m[2]] {
+			continue
+		}
+		seen[m[2]] = true
+		providers = append(providers, ProviderVersion{
+			Name:      m[2],
+			Version:   m[3],
+			Installed: m[1] == "Installed",
+		})
+	}
+	return providers
+}
+
+// ResourceAction is the action a plan takes on a resource.
+type ResourceAction string
+
+const (
+	CreateResourceAction  ResourceAction = "create"
+	UpdateResourceAction  ResourceAction = "update"
+	DeleteResourceAction  ResourceAction = "delete"
+	ReplaceResourceAction ResourceAction = "replace"
+	ReadResourceAction    ResourceAction = "read"
+	ImportResourceAction  ResourceAction = "import"
+)
+
+// resourceActionPhrases maps the phrases Terraform uses to describe what it
+// will do to a resource to the action.
+var resourceActionPhrases = map[string]ResourceAction{
+	"will be created":                 CreateResourceAction,
+	"will be updated in-place":        UpdateResourceAction,
+	"will be destroyed":               DeleteResourceAction,
+	"must be replaced":                ReplaceResourceAction,
+	"is tainted, so must be replaced": ReplaceResourceAction,
+	"will be replaced, as requested":  ReplaceResourceAction,
+	"will be read during apply":       ReadResourceAction,
+	"will be imported":                ImportResourceAction,
+}
+
+// reResourceChange matches the comment Terraform prints above each resource
+// the plan changes.
+var reResourceChange = regexp.MustCompile(`(?m)^\s*# (\S+)(?: \(deposed object \S+\))? (will be created|will be updated in-place|will be destroyed|must be replaced|is tainted, so must be replaced|will be replaced, as requested|will be read during apply|will be imported)`)
+
+// ResourceChange is a change a plan makes to a single resource.
+type ResourceChange struct {
+	Address string
+	Action  ResourceAction
+	// Stat counts the attribute lines the change adds and removes. It is nil
+	// if the resource's block couldn't be parsed.
+	Stat *ResourceStat
+}
+
+// ResourceStat is the number of attribute lines a plan adds and removes in a
+// resource, like 'git diff --stat'. An attribute changed in-place counts as
+// both.
+type ResourceStat struct {
+	Added   int
+	Removed int
+}
+
+// reResourceBlockStart matches the first line of a resource's block in a
+// plan.
+var reResourceBlockStart = regexp.MustCompile(`^\s*(?:[+~-]|-/\+|\+/-|<=)?\s*(?:resource|data) "[^"]*" "[^"]*" \{$`)
+
+// ResourceChanges extracts the changes to each resource from TerraformOutput,
+// in the order Terraform lists them.
+func (p *PlanSuccess) ResourceChanges() []ResourceChange {
+	var changes []ResourceChange
+	matches := reResourceChange.FindAllStringSubmatchIndex(p.TerraformOutput, -1)
+	for i, m := range matches {
+		end := len(p.TerraformOutput)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		changes = append(changes, ResourceChange{
+			Address: p.TerraformOutput[m[2]:m[3]],
+			Action:  resourceActionPhrases[p.TerraformOutput[m[4]:m[5]]],
+			Stat:    newResourceStat(p.TerraformOutput[m[1]:end]),
+		})
+	}
+	return changes
+}
+
+// newResourceStat counts the added and removed lines of the resource block
+// at the start of output, which follows the comment describing the change.
+// It returns nil if the block doesn't start or end where it's expected to.
+func newResourceStat(output string) *ResourceStat {
+	lines := strings.Split(strings.TrimPrefix(output, "\n"), "\n")
+	if len(lines) == 0 || !reResourceBlockStart.MatchString(lines[0]) {
+		return nil
+	}
+	stat := &ResourceStat{}
+	depth := 1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "-/+ "), strings.HasPrefix(trimmed, "+/- "), strings.HasPrefix(trimmed, "~ "):
+			stat.Added++
+			stat.Removed++
+		case strings.HasPrefix(trimmed, "+ "):
+			stat.Added++
+		case strings.HasPrefix(trimmed, "- "):
+			stat.Removed++
+		}
+		switch {
+		case strings.HasSuffix(trimmed, "{"), strings.HasSuffix(trimmed, "["), strings.HasSuffix(trimmed, "("):
+			depth++
+		case strings.HasPrefix(trimmed, "}"), strings.HasPrefix(trimmed, "]"), strings.HasPrefix(trimmed, ")"):
+			depth--
+		}
+		if depth == 0 {
+			return stat
+		}
+	}
+	return nil
+}
+
+// TaintedResources extracts the addresses of the resources TerraformOutput
+// replaces because they're tainted.
+func (p *PlanSuccess) TaintedResources() []string {
+	var addresses []string
+	for _, m := range reResourceChange.FindAllStringSubmatch(p.TerraformOutput, -1) {
+		if m[2] == "is tainted, so must be replaced" {
+			addresses = append(addresses, m[1])
+		}
+	}
+	return addresses
+}
+
+// reResourceMove matches the comment Terraform prints above a resource that
+// a moved block moves without changing it, capturing its old and new
+// addresses.
+var reResourceMove = regexp.MustCompile(`(?m)^\s*# (\S+) has moved to (\S+)\s*$`)
+
+// ResourceMove is a resource moved to a new address without other changes.
+type ResourceMove struct {
+	From string
+	To   string
+}
+
+// ResourceMoves extracts the resources TerraformOutput moves to a new
+// address without changing them.
+func (p *PlanSuccess) ResourceMoves() []ResourceMove {
+	var moves []ResourceMove
+	for _, m := range reResourceMove.FindAllStringSubmatch(p.TerraformOutput, -1) {
+		moves = append(moves, ResourceMove{From: m[1], To: m[2]})
+	}
+	return moves
+}
+
+// reSensitiveValue matches the placeholder Terraform prints instead of a
+// sensitive value.
+var reSensitiveValue = regexp.MustCompile(`\(sensitive(?: value)?\)`)
+
+// reAttributeLine matches a changed attribute in a resource block, capturing
+// its name and value.
+var reAttributeLine = regexp.MustCompile(`^\s*(?:[+~-]|-/\+|\+/-)?\s*("[^"]*"|[\w-]+)\s*=\s*(.*?)\s*$`)
+
+// reNestedBlockStart matches the start of a nested block in a resource
+// block, capturing its name.
+var reNestedBlockStart = regexp.MustCompile(`^\s*(?:[+~-]|-/\+|\+/-)?\s*([\w-]+)\s*\{$`)
+
+// SensitiveChange is an attribute whose value is changed to or from a
+// sensitive value, which Terraform doesn't show.
+type SensitiveChange struct {
+	// Address is the address of the resource the attribute belongs to.
+	Address string
+	// Attribute is the path of the attribute in the resource, ex.
+	// "settings.password".
+	Attribute string
+}
+
+// SensitiveChanges extracts the attributes TerraformOutput changes to or
+// from a sensitive value. Only the paths of the attributes are returned, never
+// their values.
+func (p *PlanSuccess) SensitiveChanges() []SensitiveChange {
+	var changes []SensitiveChange
+	var address string
+	var path []string
+	for _, line := range strings.Split(p.TerraformOutput, "\n") {
+		if m := reResourceChange.FindStringSubmatch(line); m != nil {
+			address = m[1]
+			path = nil
+			continue
+		}
+		if address == "" || reResourceBlockStart.MatchString(line) {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if m := reNestedBlockStart.FindStringSubmatch(line); m != nil {
+			path = append(path, m[1])
+			continue
+		}
+		if strings.HasPrefix(trimmed, "}") || strings.HasPrefix(trimmed, "]") {
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+			continue
+		}
+		m := reAttributeLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if strings.HasSuffix(m[2], "{") || strings.HasSuffix(m[2], "[") {
+			path = append(path, m[1])
+			continue
+		}
+		changed := strings.HasPrefix(trimmed, "+") || strings.HasPrefix(trimmed, "-") || strings.HasPrefix(trimmed, "~")
+		if changed && reSensitiveValue.MatchString(m[2]) {
+			changes = append(changes, SensitiveChange{
+				Address:   address,
+				Attribute: attributePath(append(path, m[1])),
+			})
+		}
+	}
+	return changes
+}
+
+// attributePath joins the names of an attribute and the blocks it's nested
+// in, using brackets for quoted map keys.
+func attributePath(names []string) string {
+	var b strings.Builder
+	for i, name := range names {
+		switch {
+		case strings.HasPrefix(name, `"`):
+			b.WriteString("[" + name + "]")
+		case i > 0:
+			b.WriteString("." + name)
+		default:
+			b.WriteString(name)
+		}
+	}
+	return b.String()
+}
+
 // Diff Markdown regexes
 var (
 	diffKeywordRegex = regexp.MustCompile(`(?m)^( +)([-+~]\s)(.*)(\s=\s|\s->\s|<<|\{|\(known after apply\)| {2,}[^ ]+:.*)(.*)`)
@@ -468,6 +834,13 @@ type StateRmSuccess struct {
 	RePlanCmd string
 }
 
+// FmtCheckResult is the result of running 'terraform fmt -check'.
+type FmtCheckResult struct {
+	// MisformattedFiles are the files that aren't formatted, relative to
+	// the project's dir.
+	MisformattedFiles []string
+}
+
 func (p *PolicyCheckResults) CombinedOutput() string {
 	combinedOutput := ""
 	for _, psResult := range p.PolicySetResults {
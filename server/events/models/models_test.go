@@ -391,6 +391,31 @@ func TestPlanSuccess_Summary(t *testing.T) {
 	}
 }
 
+func TestPlanSuccess_Summary_RefreshOnly(t *testing.T) {
+	cases := []struct {
+		input string
+		exp   string
+	}{
+		{
+			"Note: Objects have changed outside of Terraform\ndummy",
+			"**Drift detected:** applying this plan will update the state to match the remote objects without changing them.",
+		},
+		{
+			"No changes. Your infrastructure still matches the configuration.",
+			"No drift detected.",
+		},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("summary %d", i), func(t *testing.T) {
+			pcs := models.PlanSuccess{
+				TerraformOutput: c.input,
+				RefreshOnly:     true,
+			}
+			Equals(t, c.exp, pcs.Summary())
+		})
+	}
+}
+
 func TestPlanSuccess_DiffSummary(t *testing.T) {
 	cases := []struct {
 		input string
@@ -423,6 +448,127 @@ func TestPlanSuccess_DiffSummary(t *testing.T) {
 	}
 }
 
+func TestPlanSuccess_DiffSummary_DependenciesUnchanged(t *testing.T) {
+	pcs := models.PlanSuccess{
+		TerraformOutput:       "dummy\nNo changes. Your infrastructure matches the configuration.",
+		DependenciesUnchanged: true,
+	}
+	Equals(t, "No changes and dependencies unchanged.", pcs.DiffSummary())
+
+	pcs.TerraformOutput = "dummy\nPlan: 1 to add, 0 to change, 0 to destroy."
+	Equals(t, "Plan: 1 to add, 0 to change, 0 to destroy.", pcs.DiffSummary())
+}
+
+func TestPlanSuccess_Deprecations(t *testing.T) {
+	cases := []struct {
+		input string
+		exp   []string
+	}{
+		{
+			"dummy\nPlan: 1 to add, 0 to change, 0 to destroy.",
+			nil,
+		},
+		{
+			"╷\n│ Warning: Deprecated attribute\n│ \n│   on main.tf line 3\n╵\nDeprecated: use \"bucket_acl\" instead\nPlan: 1 to add, 0 to change, 0 to destroy.",
+			[]string{"Warning: Deprecated attribute", "Deprecated: use \"bucket_acl\" instead"},
+		},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("deprecations %d", i), func(t *testing.T) {
+			pcs := models.PlanSuccess{
+				TerraformOutput: c.input,
+			}
+			Equals(t, c.exp, pcs.Deprecations())
+		})
+	}
+}
+
+func TestPlanSuccess_AuthNotices(t *testing.T) {
+	cases := []struct {
+		input string
+		exp   []string
+	}{
+		{
+			"╷\n│ Warning: Deprecated attribute\n╵\nPlan: 1 to add, 0 to change, 0 to destroy.",
+			nil,
+		},
+		{
+			"╷\n│ Warning: AWS credentials will expire in 10 minutes\n│ \n│   with provider[\"registry.terraform.io/hashicorp/aws\"]\n╵\nWarning: Assumed role arn:aws:iam::123456789012:role/atlantis\nPlan: 1 to add, 0 to change, 0 to destroy.",
+			[]string{"Warning: AWS credentials will expire in 10 minutes", "Warning: Assumed role arn:aws:iam::123456789012:role/atlantis"},
+		},
+	}
+	for i, c := range cases {
+		t.Run(fmt.Sprintf("auth notices %d", i), func(t *testing.T) {
+			pcs := models.PlanSuccess{
+				TerraformOutput: c.input,
+			}
+			Equals(t, c.exp, pcs.AuthNotices())
+		})
+	}
+}
+
+func TestNeedsInitUpgrade(t *testing.T) {
+	Equals(t, true, models.NeedsInitUpgrade(`│ Error: Inconsistent dependency lock file
+│
+│ To update the locked dependency selections to match a changed
+│ configuration, run:
+│   terraform init -upgrade`))
+	Equals(t, true, models.NeedsInitUpgrade(`To upgrade to newer versions, run "tofu init -upgrade".`))
+	Equals(t, false, models.NeedsInitUpgrade("Plan: 1 to add, 0 to change, 0 to destroy."))
+}
+
+func TestParseDriftedResources(t *testing.T) {
+	output := `Note: Objects have changed outside of Terraform
+
+Terraform detected the following changes made outside of Terraform since the
+last "terraform apply":
+
+  # aws_instance.web has changed
+  ~ resource "aws_instance" "web" {
+      ~ tags = {
+          + "Owner" = "ops"
+        }
+    }
+
+  # module.db.aws_db_instance.main has been deleted
+  - resource "aws_db_instance" "main" {
+    }
+
+Plan: 0 to add, 1 to change, 0 to destroy.`
+	Equals(t, []string{"aws_instance.web", "module.db.aws_db_instance.main"}, models.ParseDriftedResources(output))
+	Equals(t, []string(nil), models.ParseDriftedResources("No changes. Infrastructure is up-to-date."))
+}
+
+func TestPlanSuccess_Providers(t *testing.T) {
+	pcs := models.PlanSuccess{
+		TerraformOutput: `Initializing provider plugins...
+- Finding hashicorp/aws versions matching "~> 5.0"...
+- Installing hashicorp/aws v5.17.0...
+- Installed hashicorp/aws v5.17.0 (signed by HashiCorp)
+- Using previously-installed hashicorp/random v3.5.1
+- Using previously-installed hashicorp/random v3.5.1
+
+Plan: 1 to add, 0 to change, 0 to destroy.`,
+	}
+	Equals(t, []models.ProviderVersion{
+		{Name: "hashicorp/aws", Version: "5.17.0", Installed: true},
+		{Name: "hashicorp/random", Version: "3.5.1"},
+	}, pcs.Providers())
+
+	pcs = models.PlanSuccess{TerraformOutput: "Plan: 1 to add, 0 to change, 0 to destroy."}
+	Equals(t, []models.ProviderVersion(nil), pcs.Providers())
+}
+
+func TestPlanSuccess_WorkspaceNotices(t *testing.T) {
+	pcs := models.PlanSuccess{
+		TerraformOutput: "Created and switched to workspace \"staging\"!\n\nYou're now on a new, empty workspace.\nPlan: 1 to add, 0 to change, 0 to destroy.",
+	}
+	Equals(t, []string{"Created and switched to workspace \"staging\"!"}, pcs.WorkspaceNotices())
+
+	pcs = models.PlanSuccess{TerraformOutput: "Plan: 1 to add, 0 to change, 0 to destroy."}
+	Equals(t, []string(nil), pcs.WorkspaceNotices())
+}
+
 func TestPolicyCheckResults_Summary(t *testing.T) {
 	cases := []struct {
 		description      string
@@ -706,3 +852,192 @@ func TestPlanSuccessStats(t *testing.T) {
 		})
 	}
 }
+
+func TestPlanSuccess_ResourceChanges(t *testing.T) {
+	pcs := models.PlanSuccess{
+		TerraformOutput: `Terraform will perform the following actions:
+
+  # aws_instance.new will be created
+  + resource "aws_instance" "new" {
+    }
+
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+    }
+
+  # module.db.aws_db_instance.main must be replaced
+-/+ resource "aws_db_instance" "main" {
+    }
+
+  # aws_instance.old will be destroyed
+  - resource "aws_instance" "old" {
+    }
+
+  # aws_instance.old (deposed object 1a2b3c) will be destroyed
+  - resource "aws_instance" "old" {
+    }
+
+  # aws_instance.bad is tainted, so must be replaced
+-/+ resource "aws_instance" "bad" {
+    }
+
+  # data.aws_ami.ubuntu will be read during apply
+ <= data "aws_ami" "ubuntu" {
+    }
+
+  # aws_s3_bucket.imported will be imported
+    resource "aws_s3_bucket" "imported" {
+    }
+
+Plan: 1 to import, 3 to add, 1 to change, 4 to destroy.`,
+	}
+	Equals(t, []models.ResourceChange{
+		{Address: "aws_instance.new", Action: models.CreateResourceAction, Stat: &models.ResourceStat{}},
+		{Address: "aws_instance.web", Action: models.UpdateResourceAction, Stat: &models.ResourceStat{}},
+		{Address: "module.db.aws_db_instance.main", Action: models.ReplaceResourceAction, Stat: &models.ResourceStat{}},
+		{Address: "aws_instance.old", Action: models.DeleteResourceAction, Stat: &models.ResourceStat{}},
+		{Address: "aws_instance.old", Action: models.DeleteResourceAction, Stat: &models.ResourceStat{}},
+		{Address: "aws_instance.bad", Action: models.ReplaceResourceAction, Stat: &models.ResourceStat{}},
+		{Address: "data.aws_ami.ubuntu", Action: models.ReadResourceAction, Stat: &models.ResourceStat{}},
+		{Address: "aws_s3_bucket.imported", Action: models.ImportResourceAction, Stat: &models.ResourceStat{}},
+	}, pcs.ResourceChanges())
+
+	pcs = models.PlanSuccess{TerraformOutput: "No changes. Infrastructure is up-to-date."}
+	Equals(t, []models.ResourceChange(nil), pcs.ResourceChanges())
+}
+
+func TestPlanSuccess_ResourceChanges_Stat(t *testing.T) {
+	pcs := models.PlanSuccess{
+		TerraformOutput: `Terraform will perform the following actions:
+
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+        id            = "i-123"
+      ~ instance_type = "t2.micro" -> "t3.micro"
+      + monitoring    = true
+      - user_data     = "abc" -> null
+      ~ tags          = {
+          + "team" = "platform"
+        }
+        # (10 unchanged attributes hidden)
+    }
+
+  # aws_instance.new will be created
+  + resource "aws_instance" "new" {
+      + ami = "ami-123"
+      + id  = (known after apply)
+    }
+
+  # aws_instance.cut will be destroyed
+  - resource "aws_instance" "cut" {
+      - ami = "ami-123" -> null
+
+Plan: 1 to add, 1 to change, 1 to destroy.`,
+	}
+	Equals(t, []models.ResourceChange{
+		{Address: "aws_instance.web", Action: models.UpdateResourceAction, Stat: &models.ResourceStat{Added: 4, Removed: 3}},
+		{Address: "aws_instance.new", Action: models.CreateResourceAction, Stat: &models.ResourceStat{Added: 2}},
+		{Address: "aws_instance.cut", Action: models.DeleteResourceAction},
+	}, pcs.ResourceChanges())
+}
+
+func TestPlanSuccess_TaintedResources(t *testing.T) {
+	pcs := models.PlanSuccess{
+		TerraformOutput: `Terraform will perform the following actions:
+
+  # aws_instance.bad is tainted, so must be replaced
+-/+ resource "aws_instance" "bad" {
+    }
+
+  # module.db.aws_db_instance.main must be replaced
+-/+ resource "aws_db_instance" "main" {
+    }
+
+  # module.web.aws_instance.worse is tainted, so must be replaced
+-/+ resource "aws_instance" "worse" {
+    }
+
+Plan: 2 to add, 0 to change, 2 to destroy.`,
+	}
+	Equals(t, []string{"aws_instance.bad", "module.web.aws_instance.worse"}, pcs.TaintedResources())
+
+	pcs = models.PlanSuccess{TerraformOutput: "No changes. Infrastructure is up-to-date."}
+	Equals(t, []string(nil), pcs.TaintedResources())
+}
+
+func TestPlanSuccess_ResourceMoves(t *testing.T) {
+	pcs := models.PlanSuccess{
+		TerraformOutput: `Terraform will perform the following actions:
+
+  # aws_instance.old has moved to aws_instance.new
+    resource "aws_instance" "new" {
+        id = "i-123"
+    }
+
+  # module.db.aws_db_instance.main will be updated in-place
+  # (moved from aws_db_instance.main)
+  ~ resource "aws_db_instance" "main" {
+      ~ instance_class = "db.t3.micro" -> "db.t3.small"
+    }
+
+  # module.web["a"].aws_instance.this has moved to module.web["b"].aws_instance.this
+    resource "aws_instance" "this" {
+    }
+
+Plan: 0 to add, 1 to change, 0 to destroy.`,
+	}
+	Equals(t, []models.ResourceMove{
+		{From: "aws_instance.old", To: "aws_instance.new"},
+		{From: `module.web["a"].aws_instance.this`, To: `module.web["b"].aws_instance.this`},
+	}, pcs.ResourceMoves())
+
+	pcs = models.PlanSuccess{TerraformOutput: "No changes. Infrastructure is up-to-date."}
+	Equals(t, []models.ResourceMove(nil), pcs.ResourceMoves())
+}
+
+func TestPlanSuccess_SensitiveChanges(t *testing.T) {
+	pcs := models.PlanSuccess{
+		TerraformOutput: `Terraform will perform the following actions:
+
+  # aws_db_instance.main will be updated in-place
+  ~ resource "aws_db_instance" "main" {
+        id       = "db-123"
+      ~ password = (sensitive value)
+      ~ username = "admin" -> (sensitive value)
+        # (3 unchanged attributes hidden)
+
+      ~ settings {
+          ~ api_key = (sensitive value)
+            region  = "us-east-1"
+        }
+    }
+
+  # aws_ssm_parameter.token will be created
+  + resource "aws_ssm_parameter" "token" {
+      + name  = "token"
+      + tags  = {
+          + "secret" = (sensitive)
+          + "team"   = "platform"
+        }
+      + value = (sensitive value)
+    }
+
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+        key = (sensitive value)
+      ~ ami = "ami-1" -> "ami-2"
+    }
+
+Plan: 1 to add, 2 to change, 0 to destroy.`,
+	}
+	Equals(t, []models.SensitiveChange{
+		{Address: "aws_db_instance.main", Attribute: "password"},
+		{Address: "aws_db_instance.main", Attribute: "username"},
+		{Address: "aws_db_instance.main", Attribute: "settings.api_key"},
+		{Address: "aws_ssm_parameter.token", Attribute: `tags["secret"]`},
+		{Address: "aws_ssm_parameter.token", Attribute: "value"},
+	}, pcs.SensitiveChanges())
+
+	pcs = models.PlanSuccess{TerraformOutput: "No changes. Infrastructure is up-to-date."}
+	Equals(t, []models.SensitiveChange(nil), pcs.SensitiveChanges())
+}
@@ -390,10 +390,11 @@ func (p *DefaultProjectCommandRunner) doApprovePolicies(ctx command.ProjectConte
 					allPassed = false
 				}
 				prjPolicySetResults = append(prjPolicySetResults, models.PolicySetResult{
-					PolicySetName: policySet.Name,
-					Passed:        policyStatus.Passed,
-					CurApprovals:  prjPolicyStatus[i].Approvals,
-					ReqApprovals:  policySet.ApproveCount,
+					PolicySetName:   policySet.Name,
+					Passed:          policyStatus.Passed,
+					CurApprovals:    prjPolicyStatus[i].Approvals,
+					ReqApprovals:    policySet.ApproveCount,
+					PolicySetSource: policySet.Path,
 				})
 			}
 		}
@@ -466,6 +467,12 @@ func (p *DefaultProjectCommandRunner) doPolicyCheck(ctx command.ProjectContext)
 	outputs, err := p.runSteps(ctx.Steps, ctx, absPath)
 	var errs error
 	if err != nil {
+		// Collect the errors from the policy check itself rather than from
+		// the phase they occurred in.
+		var phaseErr command.PhaseError
+		if errors.As(err, &phaseErr) {
+			err = phaseErr.Err
+		}
 		for {
 			err = errors.Unwrap(err)
 			if err == nil {
@@ -568,18 +575,32 @@ func (p *DefaultProjectCommandRunner) doPlan(ctx command.ProjectContext) (*model
 		if unlockErr := lockAttempt.UnlockFn(); unlockErr != nil {
 			ctx.Log.Err("error unlocking state after plan error: %v", unlockErr)
 		}
-		return nil, "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
+		return nil, "", fmt.Errorf("%w\n%s", err, strings.Join(outputs, "\n"))
 	}
 
+	output := strings.Join(outputs, "\n")
 	return &models.PlanSuccess{
-		LockURL:         p.LockURLGenerator.GenerateLockURL(lockAttempt.LockKey),
-		TerraformOutput: strings.Join(outputs, "\n"),
-		RePlanCmd:       ctx.RePlanCmd,
-		ApplyCmd:        ctx.ApplyCmd,
-		MergedAgain:     mergedAgain,
+		LockURL:          p.LockURLGenerator.GenerateLockURL(lockAttempt.LockKey),
+		TerraformOutput:  output,
+		RePlanCmd:        ctx.RePlanCmd,
+		ApplyCmd:         ctx.ApplyCmd,
+		MergedAgain:      mergedAgain,
+		RefreshOnly:      hasCommentArg(ctx.EscapedCommentArgs, "-refresh-only"),
+		DriftedResources: models.ParseDriftedResources(output),
 	}, "", nil
 }
 
+// hasCommentArg returns true if arg was one of the extra arguments added to
+// the atlantis comment. escapedArgs are escaped character by character.
+func hasCommentArg(escapedArgs []string, arg string) bool {
+	for _, escaped := range escapedArgs {
+		if strings.ReplaceAll(escaped, "\\", "") == arg {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *DefaultProjectCommandRunner) doApply(ctx command.ProjectContext) (applyOut string, failure string, err error) {
 	repoDir, err := p.WorkingDir.GetWorkingDir(ctx.Pull.BaseRepo, ctx.Pull, ctx.Workspace)
 	if err != nil {
@@ -617,7 +638,7 @@ func (p *DefaultProjectCommandRunner) doApply(ctx command.ProjectContext) (apply
 	})
 
 	if err != nil {
-		return "", "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
+		return "", "", fmt.Errorf("%w\n%s", err, strings.Join(outputs, "\n"))
 	}
 
 	return strings.Join(outputs, "\n"), "", nil
@@ -645,7 +666,7 @@ func (p *DefaultProjectCommandRunner) doVersion(ctx command.ProjectContext) (ver
 
 	outputs, err := p.runSteps(ctx.Steps, ctx, absPath)
 	if err != nil {
-		return "", "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
+		return "", "", fmt.Errorf("%w\n%s", err, strings.Join(outputs, "\n"))
 	}
 
 	return strings.Join(outputs, "\n"), "", nil
@@ -686,7 +707,7 @@ func (p *DefaultProjectCommandRunner) doImport(ctx command.ProjectContext) (out
 
 	outputs, err := p.runSteps(ctx.Steps, ctx, projAbsPath)
 	if err != nil {
-		return nil, "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
+		return nil, "", fmt.Errorf("%w\n%s", err, strings.Join(outputs, "\n"))
 	}
 
 	// after import, re-plan command is required without import args
@@ -727,7 +748,7 @@ func (p *DefaultProjectCommandRunner) doStateRm(ctx command.ProjectContext) (out
 
 	outputs, err := p.runSteps(ctx.Steps, ctx, projAbsPath)
 	if err != nil {
-		return nil, "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
+		return nil, "", fmt.Errorf("%w\n%s", err, strings.Join(outputs, "\n"))
 	}
 
 	// after state rm, re-plan command is required without state rm args
@@ -778,7 +799,7 @@ func (p *DefaultProjectCommandRunner) runSteps(steps []valid.Step, ctx command.P
 			outputs = append(outputs, out)
 		}
 		if err != nil {
-			return outputs, err
+			return outputs, command.PhaseError{Phase: step.StepName, Err: err}
 		}
 	}
 	return outputs, nil
@@ -524,6 +524,10 @@ func TestDefaultProjectCommandRunner_ApplyRunStepFailure(t *testing.T) {
 
 	res := runner.Apply(ctx)
 	Assert(t, res.ApplySuccess == "", "exp apply failure")
+	var phaseErr command.PhaseError
+	Assert(t, errors.As(res.Error, &phaseErr), "exp phase error, got %v", res.Error)
+	Equals(t, "apply", phaseErr.Phase)
+	Equals(t, "something went wrong\napply", res.Error.Error())
 
 	mockApply.VerifyWasCalledOnce().Run(ctx, nil, repoDir, expEnvs)
 }
@@ -0,0 +1,43 @@
+package events
+
+import (
+	"testing"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/models/testdata"
+	vcsmocks "github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+func TestUpdatePull_QuietNoChanges(t *testing.T) {
+	RegisterMockTestingT(t)
+	vcsClient := vcsmocks.NewMockClient()
+	renderer := NewMarkdownRenderer(false, false, false, false, false, false, "", "atlantis", false, MarkdownRendererOptions{})
+	renderer.Quiet = true
+	updater := &PullUpdater{
+		HidePrevPlanComments: true,
+		VCSClient:            vcsClient,
+		MarkdownRenderer:     renderer,
+	}
+	ctx := &command.Context{
+		Log:  logging.NewNoopLogger(t),
+		Pull: testdata.Pull,
+	}
+	res := command.Result{ProjectResults: []command.ProjectResult{{
+		Command:     command.Plan,
+		Workspace:   "default",
+		RepoRelDir:  "path",
+		PlanSuccess: &models.PlanSuccess{TerraformOutput: "No changes. Infrastructure is up-to-date."},
+	}}}
+
+	updater.updatePull(ctx, AutoplanCommand{}, res)
+	vcsClient.VerifyWasCalled(Never()).HidePrevCommandComments(Any[models.Repo](), Any[int](), Any[string]())
+	vcsClient.VerifyWasCalled(Never()).CreateComment(Any[models.Repo](), Any[int](), Any[string](), Any[string]())
+
+	res.ProjectResults[0].PlanSuccess = &models.PlanSuccess{TerraformOutput: "Plan: 1 to add, 0 to change, 0 to destroy."}
+	updater.updatePull(ctx, AutoplanCommand{}, res)
+	vcsClient.VerifyWasCalledOnce().HidePrevCommandComments(testdata.Pull.BaseRepo, testdata.Pull.Num, "Plan")
+	vcsClient.VerifyWasCalledOnce().CreateComment(Eq(testdata.Pull.BaseRepo), Eq(testdata.Pull.Num), Any[string](), Eq("plan"))
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// resultTemplate returns the name of the template to render result with and
+// its data, for the renderers sharing the "error", "failure", "planSuccess"
+// and "output" templates, ex. SlackRenderer. Secrets matching maskPatterns
+// are masked in the outputs.
+func resultTemplate(result command.ProjectResult, common commonData, maskPatterns []*regexp.Regexp) (string, interface{}) {
+	switch {
+	case result.Error != nil:
+		return "error", errData{Error: strings.TrimSpace(result.Error.Error()), commonData: common}
+	case result.Failure != "":
+		return "failure", failureData{Failure: result.Failure, commonData: common}
+	case result.PlanSuccess != nil:
+		planSuccess := *result.PlanSuccess
+		// Only trim blank lines so the first line keeps its indentation.
+		planSuccess.TerraformOutput = strings.Trim(maskSecrets(planSuccess.TerraformOutput, maskPatterns), "\n")
+		return "planSuccess", planSuccessData{
+			PlanSuccess:    planSuccess,
+			PlanWasDeleted: common.PlansDeleted,
+			DisableApply:   common.DisableApply,
+			PlanStats:      planSuccess.Stats(),
+		}
+	case result.ApplySuccess != "":
+		return "output", applySuccessData{Output: strings.TrimSpace(maskSecrets(result.ApplySuccess, maskPatterns))}
+	case result.VersionSuccess != "":
+		return "output", applySuccessData{Output: strings.TrimSpace(maskSecrets(result.VersionSuccess, maskPatterns))}
+	case result.ImportSuccess != nil:
+		return "output", applySuccessData{Output: strings.TrimSpace(maskSecrets(result.ImportSuccess.Output, maskPatterns))}
+	case result.StateRmSuccess != nil:
+		return "output", applySuccessData{Output: strings.TrimSpace(maskSecrets(result.StateRmSuccess.Output, maskPatterns))}
+	}
+	return "", nil
+}
+
+// maskSecrets replaces everything in output matching one of patterns with
+// "***".
+func maskSecrets(output string, patterns []*regexp.Regexp) string {
+	for _, pattern := range patterns {
+		output = pattern.ReplaceAllString(output, "***")
+	}
+	return output
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// RSTRenderer renders responses as reStructuredText, ex. to publish summaries
+// of changes in Sphinx docs.
+type RSTRenderer struct {
+	executableName string
+	templates      *template.Template
+	// disableApply leaves out the comment to apply plans.
+	disableApply bool
+	// secretMaskPatterns are matched against the outputs and every match is
+	// replaced with "***" before the output is rendered.
+	secretMaskPatterns []*regexp.Regexp
+}
+
+// NewRSTRenderer returns a renderer whose output refers to the Atlantis
+// binary as executableName.
+func NewRSTRenderer(executableName string, disableApply bool, secretMaskPatterns []*regexp.Regexp) *RSTRenderer {
+	funcs := sprig.TxtFuncMap()
+	funcs["codeBlock"] = rstCodeBlock
+	funcs["literal"] = rstLiteral
+	funcs["underline"] = rstUnderline
+	funcs["projectTitle"] = rstProjectTitle
+	return &RSTRenderer{
+		executableName:     executableName,
+		disableApply:       disableApply,
+		secretMaskPatterns: secretMaskPatterns,
+		templates:          template.Must(template.New("").Funcs(funcs).ParseFS(templatesFS, "templates/rst/*.tmpl")),
+	}
+}
+
+// Render formats the data into a reStructuredText string.
+func (r *RSTRenderer) Render(res command.Result, cmdName command.Name, subCmd string) string {
+	common := commonData{
+		Command:        cmdName.TitleString(),
+		SubCommand:     subCmd,
+		ExecutableName: r.executableName,
+		PlansDeleted:   res.PlansDeleted,
+		DisableApply:   r.disableApply,
+	}
+	if res.Error != nil {
+		return r.render("error", errData{Error: strings.TrimSpace(res.Error.Error()), commonData: common})
+	}
+	if res.Failure != "" {
+		return r.render("failure", failureData{Failure: res.Failure, commonData: common})
+	}
+
+	var results []projectResultTmplData
+	for i, result := range res.ProjectResults {
+		resultData := projectResultTmplData{
+			Workspace:   result.Workspace,
+			RepoRelDir:  result.RepoRelDir,
+			ProjectName: result.ProjectName,
+			Num:         i + 1,
+		}
+		if name, data := resultTemplate(result, common, r.secretMaskPatterns); name != "" {
+			resultData.Rendered = r.render(name, data)
+		}
+		resultData.NoChanges = result.PlanSuccess != nil && result.PlanSuccess.NoChanges()
+		results = append(results, resultData)
+	}
+	return r.render("results", resultData{Results: results, commonData: common})
+}
+
+func (r *RSTRenderer) render(name string, data interface{}) string {
+	buf := &bytes.Buffer{}
+	if err := r.templates.ExecuteTemplate(buf, name, data); err != nil {
+		return fmt.Sprintf("Failed to render template, this is a bug: %v", err)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// rstCodeBlock returns a code-block directive highlighting code as language.
+func rstCodeBlock(language string, code string) string {
+	lines := strings.Split(code, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = "   " + line
+		} else {
+			lines[i] = ""
+		}
+	}
+	return fmt.Sprintf(".. code-block:: %s\n\n%s", language, strings.Join(lines, "\n"))
+}
+
+// rstLiteral returns s as inline literal text.
+func rstLiteral(s string) string {
+	return "``" + s + "``"
+}
+
+// rstUnderline returns the line to put under title to make it a section
+// header, made of char.
+func rstUnderline(char string, title string) string {
+	return strings.Repeat(char, utf8.RuneCountInString(title))
+}
+
+// rstProjectTitle returns the title of the section for result.
+func rstProjectTitle(result projectResultTmplData) string {
+	title := fmt.Sprintf("%d. ", result.Num)
+	if result.ProjectName != "" {
+		title += "project: " + rstLiteral(result.ProjectName) + " "
+	}
+	return title + "dir: " + rstLiteral(result.RepoRelDir) + " workspace: " + rstLiteral(result.Workspace)
+}
@@ -0,0 +1,114 @@
+package events_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestRSTRenderer_Render(t *testing.T) {
+	cases := []struct {
+		Description string
+		Result      command.Result
+		Expected    string
+	}{
+		{
+			"plan",
+			command.Result{ProjectResults: []command.ProjectResult{
+				{
+					Workspace:  "default",
+					RepoRelDir: "path",
+					PlanSuccess: &models.PlanSuccess{
+						TerraformOutput: "  + resource \"null_resource\" \"a\" {\n      + id = (known after apply)\n    }\n\nPlan: 1 to add, 0 to change, 0 to destroy.\n",
+						LockURL:         "lock-url",
+						RePlanCmd:       "atlantis plan -d path",
+						ApplyCmd:        "atlantis apply -d path",
+					},
+				},
+				{
+					Workspace:   "staging",
+					RepoRelDir:  "path2",
+					ProjectName: "projectname",
+					Error:       errors.New("error"),
+				},
+			}},
+			`Plan Results
+============
+
+1. dir: ` + "``path``" + ` workspace: ` + "``default``" + `
+---------------------------------------
+
+.. code-block:: diff
+
+     + resource "null_resource" "a" {
+         + id = (known after apply)
+       }
+
+   Plan: 1 to add, 0 to change, 0 to destroy.
+
+* To **apply** this plan, comment: ` + "``atlantis apply -d path``" + `
+* To **plan** this project again, comment: ` + "``atlantis plan -d path``" + `
+
+2. project: ` + "``projectname``" + ` dir: ` + "``path2``" + ` workspace: ` + "``staging``" + `
+-----------------------------------------------------------------
+
+**Plan Error**
+
+.. code-block:: text
+
+   error`,
+		},
+		{
+			"no projects",
+			command.Result{},
+			"Plan Results\n============\n\nNo projects matched this command.",
+		},
+		{
+			"error",
+			command.Result{Error: errors.New("error")},
+			"**Plan Error**\n\n.. code-block:: text\n\n   error",
+		},
+		{
+			"failure",
+			command.Result{Failure: "failure"},
+			"**Plan Failed**: failure",
+		},
+	}
+
+	r := events.NewRSTRenderer("atlantis", false, nil)
+	for _, c := range cases {
+		t.Run(c.Description, func(t *testing.T) {
+			Equals(t, c.Expected, r.Render(c.Result, command.Plan, ""))
+		})
+	}
+}
+
+func TestRSTRenderer_RenderMasksSecretsWithoutApply(t *testing.T) {
+	r := events.NewRSTRenderer("atlantis", true, []*regexp.Regexp{regexp.MustCompile(`hunter\d`)})
+	res := command.Result{ProjectResults: []command.ProjectResult{{
+		Workspace:  "default",
+		RepoRelDir: "path",
+		PlanSuccess: &models.PlanSuccess{
+			TerraformOutput: "  ~ password = \"hunter1\" -> \"hunter2\"",
+			RePlanCmd:       "atlantis plan -d path",
+			ApplyCmd:        "atlantis apply -d path",
+		},
+	}}}
+
+	Equals(t, `Plan Results
+============
+
+1. dir: `+"``path``"+` workspace: `+"``default``"+`
+---------------------------------------
+
+.. code-block:: diff
+
+     ~ password = "***" -> "***"
+
+* To **plan** this project again, comment: `+"``atlantis plan -d path``", r.Render(res, command.Plan, ""))
+}
@@ -0,0 +1,97 @@
+package events
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// maxSlackBlockLength is the maximum length of the text of a Slack section
+// block.
+const maxSlackBlockLength = 3000
+
+// slackTruncatedFooter ends Slack blocks that were truncated.
+const slackTruncatedFooter = "_This block was truncated due to Slack's size limits._"
+
+// SlackRenderer renders responses as Slack mrkdwn.
+type SlackRenderer struct {
+	executableName string
+	templates      *template.Template
+	// disableApply leaves out the comment to apply plans.
+	disableApply bool
+	// secretMaskPatterns are matched against the outputs and every match is
+	// replaced with "***" before the output is rendered.
+	secretMaskPatterns []*regexp.Regexp
+}
+
+// NewSlackRenderer returns a renderer whose output refers to the Atlantis
+// binary as executableName.
+func NewSlackRenderer(executableName string, disableApply bool, secretMaskPatterns []*regexp.Regexp) *SlackRenderer {
+	funcs := sprig.TxtFuncMap()
+	funcs["escape"] = slackEscape
+	return &SlackRenderer{
+		executableName:     executableName,
+		disableApply:       disableApply,
+		secretMaskPatterns: secretMaskPatterns,
+		templates:          template.Must(template.New("").Funcs(funcs).ParseFS(templatesFS, "templates/slack/*.tmpl")),
+	}
+}
+
+// Render formats the data into Slack mrkdwn, split into the texts of section
+// blocks: a summary followed by one block per project. Each block is
+// truncated to Slack's size limit.
+func (s *SlackRenderer) Render(res command.Result, cmdName command.Name, subCmd string) []string {
+	common := commonData{
+		Command:        cmdName.TitleString(),
+		SubCommand:     subCmd,
+		ExecutableName: s.executableName,
+		PlansDeleted:   res.PlansDeleted,
+		DisableApply:   s.disableApply,
+	}
+	blocks := []string{s.render("summary", struct{ Title string }{checksTitle(res, common.Command)})}
+	switch {
+	case res.Error != nil:
+		blocks = append(blocks, s.render("error", errData{Error: strings.TrimSpace(res.Error.Error()), commonData: common}))
+	case res.Failure != "":
+		blocks = append(blocks, s.render("failure", failureData{Failure: res.Failure, commonData: common}))
+	}
+
+	for i, result := range res.ProjectResults {
+		resultData := projectResultTmplData{
+			Workspace:   result.Workspace,
+			RepoRelDir:  result.RepoRelDir,
+			ProjectName: result.ProjectName,
+			Num:         i + 1,
+		}
+		if name, data := resultTemplate(result, common, s.secretMaskPatterns); name != "" {
+			resultData.Rendered = s.render(name, data)
+		}
+		blocks = append(blocks, s.render("project", resultData))
+	}
+
+	for i, block := range blocks {
+		if len(block) > maxSlackBlockLength {
+			blocks[i] = truncateComment(block, maxSlackBlockLength-len(slackTruncatedFooter)-1, "```") + "\n" + slackTruncatedFooter
+		}
+	}
+	return blocks
+}
+
+func (s *SlackRenderer) render(name string, data interface{}) string {
+	buf := &bytes.Buffer{}
+	if err := s.templates.ExecuteTemplate(buf, name, data); err != nil {
+		return fmt.Sprintf("Failed to render template, this is a bug: %v", err)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// slackEscape escapes the characters Slack uses for control sequences in
+// mrkdwn.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,112 @@
+package events_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestSlackRenderer_Render(t *testing.T) {
+	r := events.NewSlackRenderer("atlantis", false, nil)
+	res := command.Result{ProjectResults: []command.ProjectResult{
+		{
+			Workspace:  "default",
+			RepoRelDir: "path",
+			PlanSuccess: &models.PlanSuccess{
+				TerraformOutput: "  ~ name = \"a\" -> \"b\"\n\nPlan: 0 to add, 1 to change, 0 to destroy.",
+				LockURL:         "lock-url",
+				RePlanCmd:       "atlantis plan -d path",
+				ApplyCmd:        "atlantis apply -d path",
+			},
+		},
+		{
+			Workspace:   "staging",
+			RepoRelDir:  "path2",
+			ProjectName: "projectname",
+			Error:       errors.New("error"),
+		},
+	}}
+
+	Equals(t, []string{
+		"*Plan: 1 of 2 projects failed*",
+		"*1. dir: `path` workspace: `default`*\n```\n~ name = \"a\" -&gt; \"b\"\n\nPlan: 0 to add, 1 to change, 0 to destroy.\n```\n• To *apply* this plan, comment: `atlantis apply -d path`\n• To *plan* this project again, comment: `atlantis plan -d path`",
+		"*2. project: `projectname` dir: `path2` workspace: `staging`*\n*Plan Error*\n```\nerror\n```",
+	}, r.Render(res, command.Plan, ""))
+}
+
+func TestSlackRenderer_RenderFailure(t *testing.T) {
+	r := events.NewSlackRenderer("atlantis", false, nil)
+	Equals(t, []string{
+		"*Apply Failed*",
+		"*Apply Failed*: Pull request must be approved &amp; mergeable",
+	}, r.Render(command.Result{Failure: "Pull request must be approved & mergeable"}, command.Apply, ""))
+}
+
+func TestSlackRenderer_RenderTruncated(t *testing.T) {
+	r := events.NewSlackRenderer("atlantis", false, nil)
+	res := command.Result{ProjectResults: []command.ProjectResult{{
+		Workspace:    "default",
+		RepoRelDir:   "path",
+		ApplySuccess: strings.Repeat("aws_instance.web: Creating...\n", 500),
+	}}}
+	blocks := r.Render(res, command.Apply, "")
+	Equals(t, 2, len(blocks))
+	Assert(t, len(blocks[1]) <= 3000, "block is %d long", len(blocks[1]))
+	Assert(t, strings.HasSuffix(blocks[1], "```\n_This block was truncated due to Slack's size limits._"), "got:\n%s", blocks[1])
+	Assert(t, !strings.Contains(blocks[1], "<details>"), "unexpected details in:\n%s", blocks[1])
+}
+
+func TestSlackRenderer_RenderMasksSecrets(t *testing.T) {
+	r := events.NewSlackRenderer("atlantis", false, []*regexp.Regexp{regexp.MustCompile(`hunter\d`)})
+	res := command.Result{ProjectResults: []command.ProjectResult{
+		{
+			Workspace:  "default",
+			RepoRelDir: "path",
+			PlanSuccess: &models.PlanSuccess{
+				TerraformOutput: "  ~ password = \"hunter1\" -> \"hunter2\"",
+				RePlanCmd:       "atlantis plan -d path",
+				ApplyCmd:        "atlantis apply -d path",
+			},
+		},
+		{
+			Workspace:    "default",
+			RepoRelDir:   "path2",
+			ApplySuccess: "password = hunter3",
+		},
+	}}
+
+	blocks := r.Render(res, command.Plan, "")
+	Equals(t, 3, len(blocks))
+	Equals(t, "*1. dir: `path` workspace: `default`*\n```\n~ password = \"***\" -&gt; \"***\"\n```\n• To *apply* this plan, comment: `atlantis apply -d path`\n• To *plan* this project again, comment: `atlantis plan -d path`", blocks[1])
+	Equals(t, "*2. dir: `path2` workspace: `default`*\n```\npassword = ***\n```", blocks[2])
+}
+
+func TestSlackRenderer_RenderWithoutApply(t *testing.T) {
+	res := command.Result{ProjectResults: []command.ProjectResult{{
+		Workspace:  "default",
+		RepoRelDir: "path",
+		PlanSuccess: &models.PlanSuccess{
+			TerraformOutput: "Plan: 0 to add, 1 to change, 0 to destroy.",
+			RePlanCmd:       "atlantis plan -d path",
+			ApplyCmd:        "atlantis apply -d path",
+		},
+	}}}
+
+	t.Run("apply disabled", func(t *testing.T) {
+		r := events.NewSlackRenderer("atlantis", true, nil)
+		Equals(t, "*1. dir: `path` workspace: `default`*\n```\nPlan: 0 to add, 1 to change, 0 to destroy.\n```\n• To *plan* this project again, comment: `atlantis plan -d path`", r.Render(res, command.Plan, "")[1])
+	})
+
+	t.Run("plans deleted", func(t *testing.T) {
+		r := events.NewSlackRenderer("atlantis", false, nil)
+		deleted := res
+		deleted.PlansDeleted = true
+		Equals(t, "*1. dir: `path` workspace: `default`*\n```\nPlan: 0 to add, 1 to change, 0 to destroy.\n```\nThis plan was not saved because one or more projects failed and automerge requires all plans pass.", r.Render(deleted, command.Plan, "")[1])
+	})
+}
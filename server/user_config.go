@@ -1,8 +1,15 @@
 package server
 
 import (
+	"encoding/json"
+	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/pkg/errors"
+
+	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/logging"
 )
@@ -127,6 +134,63 @@ type UserConfig struct {
 	WriteGitCreds          bool            `mapstructure:"write-git-creds"`
 	WebsocketCheckOrigin   bool            `mapstructure:"websocket-check-origin"`
 	UseTFPluginCache         bool            `mapstructure:"use-tf-plugin-cache"`
+
+	// Markdown renderer options.
+	MarkdownCodeFence                     string `mapstructure:"markdown-code-fence"`
+	MarkdownCollapseComputedAttributes    bool   `mapstructure:"markdown-collapse-computed-attributes"`
+	MarkdownCollapseDataSourceReads       bool   `mapstructure:"markdown-collapse-data-source-reads"`
+	MarkdownCollapseDiffContext           bool   `mapstructure:"markdown-collapse-diff-context"`
+	MarkdownCollapseProvisionerOutput     bool   `mapstructure:"markdown-collapse-provisioner-output"`
+	MarkdownCommentBudget                 string `mapstructure:"markdown-comment-budget"`
+	MarkdownDiffContextLines              int    `mapstructure:"markdown-diff-context-lines"`
+	MarkdownDirectoryTableColumns         int    `mapstructure:"markdown-directory-table-columns"`
+	MarkdownDirectoryTableThreshold       int    `mapstructure:"markdown-directory-table-threshold"`
+	MarkdownDisableNewlineNormalization   bool   `mapstructure:"markdown-disable-newline-normalization"`
+	MarkdownEmbedChecksum                 bool   `mapstructure:"markdown-embed-checksum"`
+	MarkdownExpandLog                     bool   `mapstructure:"markdown-expand-log"`
+	MarkdownForceMultiLayout              bool   `mapstructure:"markdown-force-multi-layout"`
+	MarkdownGroupByOwner                  bool   `mapstructure:"markdown-group-by-owner"`
+	MarkdownGroupResourceChangesByModule  bool   `mapstructure:"markdown-group-resource-changes-by-module"`
+	MarkdownIcons                         string `mapstructure:"markdown-icons"`
+	MarkdownInlineNoChanges               bool   `mapstructure:"markdown-inline-no-changes"`
+	MarkdownKnownIssues                   string `mapstructure:"markdown-known-issues"`
+	MarkdownLocale                        string `mapstructure:"markdown-locale"`
+	MarkdownLogSummary                    string `mapstructure:"markdown-log-summary"`
+	MarkdownMaxCommentLength              int    `mapstructure:"markdown-max-comment-length"`
+	MarkdownMaxFoldDepth                  int    `mapstructure:"markdown-max-fold-depth"`
+	MarkdownMaxModuleDepth                int    `mapstructure:"markdown-max-module-depth"`
+	MarkdownMaxResourceChanges            int    `mapstructure:"markdown-max-resource-changes"`
+	MarkdownMaxWarnings                   int    `mapstructure:"markdown-max-warnings"`
+	MarkdownMinimal                       bool   `mapstructure:"markdown-minimal"`
+	MarkdownNoOutputMessage               string `mapstructure:"markdown-no-output-message"`
+	MarkdownPlanView                      string `mapstructure:"markdown-plan-view"`
+	MarkdownPrettyPrintJSON               bool   `mapstructure:"markdown-pretty-print-json"`
+	MarkdownPrintFriendly                 bool   `mapstructure:"markdown-print-friendly"`
+	MarkdownProjectPathRoot               string `mapstructure:"markdown-project-path-root"`
+	MarkdownQuiet                         bool   `mapstructure:"markdown-quiet"`
+	MarkdownReverseSectionOrder           bool   `mapstructure:"markdown-reverse-section-order"`
+	MarkdownRunbookURL                    string `mapstructure:"markdown-runbook-url"`
+	MarkdownSecretMaskPatterns            string `mapstructure:"markdown-secret-mask-patterns"`
+	MarkdownShortenModuleSources          bool   `mapstructure:"markdown-shorten-module-sources"`
+	MarkdownShowApplySnippet              bool   `mapstructure:"markdown-show-apply-snippet"`
+	MarkdownShowDebugInfo                 bool   `mapstructure:"markdown-show-debug-info"`
+	MarkdownShowGeneratedAt               bool   `mapstructure:"markdown-show-generated-at"`
+	MarkdownShowNextSteps                 bool   `mapstructure:"markdown-show-next-steps"`
+	MarkdownShowPlanDiffLineCount         bool   `mapstructure:"markdown-show-plan-diff-line-count"`
+	MarkdownShowProjectAnchors            bool   `mapstructure:"markdown-show-project-anchors"`
+	MarkdownShowResourceChanges           bool   `mapstructure:"markdown-show-resource-changes"`
+	MarkdownShowResourceStats             bool   `mapstructure:"markdown-show-resource-stats"`
+	MarkdownShowStatusTable               bool   `mapstructure:"markdown-show-status-table"`
+	MarkdownShowWelcome                   bool   `mapstructure:"markdown-show-welcome"`
+	MarkdownSortResourceChangesBySeverity bool   `mapstructure:"markdown-sort-resource-changes-by-severity"`
+	MarkdownStalePlanAge                  string `mapstructure:"markdown-stale-plan-age"`
+	MarkdownStatusEmoji                   bool   `mapstructure:"markdown-status-emoji"`
+	MarkdownSuccessMessages               string `mapstructure:"markdown-success-messages"`
+	MarkdownThreadKey                     string `mapstructure:"markdown-thread-key"`
+	MarkdownTicketLinks                   string `mapstructure:"markdown-ticket-links"`
+	MarkdownTimeZone                      string `mapstructure:"markdown-time-zone"`
+	MarkdownWelcomeMessage                string `mapstructure:"markdown-welcome-message"`
+	MarkdownWrapInRegionMarkers           bool   `mapstructure:"markdown-wrap-in-region-markers"`
 }
 
 // ToAllowCommandNames parse AllowCommands into a slice of CommandName
@@ -153,6 +217,180 @@ func (u UserConfig) ToAllowCommandNames() ([]command.Name, error) {
 	return allowCommands, nil
 }
 
+// ToMarkdownRendererOptions parses the markdown renderer options, some of
+// which are passed as JSON, ex. MarkdownIcons.
+func (u UserConfig) ToMarkdownRendererOptions() (events.MarkdownRendererOptions, error) {
+	opts := events.MarkdownRendererOptions{
+		CodeFence: u.MarkdownCodeFence,
+		CollapseComputedAttributes: u.MarkdownCollapseComputedAttributes,
+		CollapseDataSourceReads: u.MarkdownCollapseDataSourceReads,
+		CollapseDiffContext: u.MarkdownCollapseDiffContext,
+		CollapseProvisionerOutput: u.MarkdownCollapseProvisionerOutput,
+		DiffContextLines: u.MarkdownDiffContextLines,
+		DirectoryTableColumns: u.MarkdownDirectoryTableColumns,
+		DirectoryTableThreshold: u.MarkdownDirectoryTableThreshold,
+		DisableNewlineNormalization: u.MarkdownDisableNewlineNormalization,
+		EmbedChecksum: u.MarkdownEmbedChecksum,
+		ExpandLog: u.MarkdownExpandLog,
+		ForceMultiLayout: u.MarkdownForceMultiLayout,
+		GroupByOwner: u.MarkdownGroupByOwner,
+		GroupResourceChangesByModule: u.MarkdownGroupResourceChangesByModule,
+		InlineNoChanges: u.MarkdownInlineNoChanges,
+		Locale: u.MarkdownLocale,
+		LogSummary: u.MarkdownLogSummary,
+		MaxCommentLength: u.MarkdownMaxCommentLength,
+		MaxFoldDepth: u.MarkdownMaxFoldDepth,
+		MaxModuleDepth: u.MarkdownMaxModuleDepth,
+		MaxResourceChanges: u.MarkdownMaxResourceChanges,
+		MaxWarnings: u.MarkdownMaxWarnings,
+		Minimal: u.MarkdownMinimal,
+		NoOutputMessage: u.MarkdownNoOutputMessage,
+		PrettyPrintJSON: u.MarkdownPrettyPrintJSON,
+		PrintFriendly: u.MarkdownPrintFriendly,
+		ProjectPathRoot: u.MarkdownProjectPathRoot,
+		Quiet: u.MarkdownQuiet,
+		ReverseSectionOrder: u.MarkdownReverseSectionOrder,
+		RunbookURL: u.MarkdownRunbookURL,
+		ShortenModuleSources: u.MarkdownShortenModuleSources,
+		ShowApplySnippet: u.MarkdownShowApplySnippet,
+		ShowDebugInfo: u.MarkdownShowDebugInfo,
+		ShowGeneratedAt: u.MarkdownShowGeneratedAt,
+		ShowNextSteps: u.MarkdownShowNextSteps,
+		ShowPlanDiffLineCount: u.MarkdownShowPlanDiffLineCount,
+		ShowProjectAnchors: u.MarkdownShowProjectAnchors,
+		ShowResourceChanges: u.MarkdownShowResourceChanges,
+		ShowResourceStats: u.MarkdownShowResourceStats,
+		ShowStatusTable: u.MarkdownShowStatusTable,
+		ShowWelcome: u.MarkdownShowWelcome,
+		SortResourceChangesBySeverity: u.MarkdownSortResourceChangesBySeverity,
+		StatusEmoji: u.MarkdownStatusEmoji,
+		ThreadKey: u.MarkdownThreadKey,
+		WelcomeMessage: u.MarkdownWelcomeMessage,
+		WrapInRegionMarkers: u.MarkdownWrapInRegionMarkers,
+	}
+
+	if u.MarkdownSecretMaskPatterns != "" {
+		var patterns []string
+		if err := json.Unmarshal([]byte(u.MarkdownSecretMaskPatterns), &patterns); err != nil {
+			return opts, errors.Wrap(err, "parsing --markdown-secret-mask-patterns")
+		}
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return opts, errors.Wrap(err, "parsing --markdown-secret-mask-patterns")
+			}
+			opts.SecretMaskPatterns = append(opts.SecretMaskPatterns, re)
+		}
+	}
+
+	if u.MarkdownSuccessMessages != "" {
+		var messages map[string]string
+		if err := json.Unmarshal([]byte(u.MarkdownSuccessMessages), &messages); err != nil {
+			return opts, errors.Wrap(err, "parsing --markdown-success-messages")
+		}
+		opts.SuccessMessages = make(map[command.Name]string)
+		for name, message := range messages {
+			cmd, err := command.ParseCommandName(name)
+			if err != nil {
+				return opts, errors.Wrap(err, "parsing --markdown-success-messages")
+			}
+			opts.SuccessMessages[cmd] = message
+		}
+	}
+
+	if u.MarkdownCommentBudget != "" {
+		var budget events.CommentBudget
+		if err := json.Unmarshal([]byte(u.MarkdownCommentBudget), &budget); err != nil {
+			return opts, errors.Wrap(err, "parsing --markdown-comment-budget")
+		}
+		if budget.Diff < 0 || budget.Log < 0 || budget.Diff+budget.Log > 1 {
+			return opts, fmt.Errorf("invalid --markdown-comment-budget %s: the fractions must be positive and add up to at most 1", u.MarkdownCommentBudget)
+		}
+		opts.CommentBudget = &budget
+	}
+
+	switch u.MarkdownPlanView {
+	case "", events.ParsedPlanView, events.RawPlanView:
+		opts.PlanView = u.MarkdownPlanView
+	default:
+		return opts, fmt.Errorf("invalid --markdown-plan-view %q: must be %q or %q", u.MarkdownPlanView, events.ParsedPlanView, events.RawPlanView)
+	}
+
+	if u.MarkdownStalePlanAge != "" {
+		age, err := time.ParseDuration(u.MarkdownStalePlanAge)
+		if err != nil {
+			return opts, errors.Wrap(err, "parsing --markdown-stale-plan-age")
+		}
+		opts.StalePlanAge = age
+	}
+
+	if u.MarkdownIcons != "" {
+		if err := json.Unmarshal([]byte(u.MarkdownIcons), &opts.Icons); err != nil {
+			return opts, errors.Wrap(err, "parsing --markdown-icons")
+		}
+	}
+
+	if u.MarkdownTimeZone != "" {
+		location, err := time.LoadLocation(u.MarkdownTimeZone)
+		if err != nil {
+			return opts, errors.Wrap(err, "parsing --markdown-time-zone")
+		}
+		opts.Location = location
+	}
+
+	ticketLinks, err := parseMarkdownLinks(u.MarkdownTicketLinks)
+	if err != nil {
+		return opts, errors.Wrap(err, "parsing --markdown-ticket-links")
+	}
+	for _, link := range ticketLinks {
+		opts.TicketLinks = append(opts.TicketLinks, events.TicketLink(link))
+	}
+
+	knownIssues, err := parseMarkdownLinks(u.MarkdownKnownIssues)
+	if err != nil {
+		return opts, errors.Wrap(err, "parsing --markdown-known-issues")
+	}
+	for _, issue := range knownIssues {
+		opts.KnownIssues = append(opts.KnownIssues, events.KnownIssue(issue))
+	}
+
+	return opts, nil
+}
+
+// markdownLink is a regular expression and the URL it links to, as passed
+// in --markdown-ticket-links and --markdown-known-issues.
+type markdownLink struct {
+	Pattern *regexp.Regexp
+	URL     string
+}
+
+// parseMarkdownLinks parses a JSON array of objects with a "pattern" and a
+// "url" into links.
+func parseMarkdownLinks(input string) ([]markdownLink, error) {
+	if input == "" {
+		return nil, nil
+	}
+	var raw []struct {
+		Pattern string `json:"pattern"`
+		URL     string `json:"url"`
+	}
+	if err := json.Unmarshal([]byte(input), &raw); err != nil {
+		return nil, err
+	}
+	var links []markdownLink
+	for _, r := range raw {
+		if r.Pattern == "" || r.URL == "" {
+			return nil, errors.New("each link must have a pattern and a url")
+		}
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, markdownLink{Pattern: re, URL: r.URL})
+	}
+	return links, nil
+}
+
 // ToLogLevel returns the LogLevel object corresponding to the user-passed
 // log level.
 func (u UserConfig) ToLogLevel() logging.LogLevel {
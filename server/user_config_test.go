@@ -2,8 +2,10 @@ package server_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/runatlantis/atlantis/server"
+	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/logging"
 	. "github.com/runatlantis/atlantis/testing"
@@ -104,3 +106,91 @@ func TestUserConfig_ToLogLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestUserConfig_ToMarkdownRendererOptions(t *testing.T) {
+	u := server.UserConfig{
+		MarkdownShowStatusTable:    true,
+		MarkdownMaxCommentLength:   1000,
+		MarkdownCodeFence:          "~~~",
+		MarkdownSecretMaskPatterns: `["password=\\S+"]`,
+		MarkdownSuccessMessages:    `{"plan": "Plan succeeded."}`,
+		MarkdownCommentBudget:      `{"diff": 0.6, "log": 0.2}`,
+		MarkdownPlanView:           "parsed",
+		MarkdownStalePlanAge:       "2h",
+		MarkdownIcons:              `{"success": ":white_check_mark:"}`,
+		MarkdownTimeZone:           "UTC",
+		MarkdownTicketLinks:        `[{"pattern": "JIRA-\\d+", "url": "https://jira.example.com/browse/$0"}]`,
+		MarkdownKnownIssues:        `[{"pattern": "state lock", "url": "https://kb.example.com/state-lock"}]`,
+	}
+	opts, err := u.ToMarkdownRendererOptions()
+	Ok(t, err)
+	Equals(t, true, opts.ShowStatusTable)
+	Equals(t, 1000, opts.MaxCommentLength)
+	Equals(t, "~~~", opts.CodeFence)
+	Equals(t, 1, len(opts.SecretMaskPatterns))
+	Equals(t, `password=\S+`, opts.SecretMaskPatterns[0].String())
+	Equals(t, map[command.Name]string{command.Plan: "Plan succeeded."}, opts.SuccessMessages)
+	Equals(t, &events.CommentBudget{Diff: 0.6, Log: 0.2}, opts.CommentBudget)
+	Equals(t, events.ParsedPlanView, opts.PlanView)
+	Equals(t, 2*time.Hour, opts.StalePlanAge)
+	Equals(t, map[string]string{events.SuccessIcon: ":white_check_mark:"}, opts.Icons)
+	Equals(t, time.UTC, opts.Location)
+	Equals(t, 1, len(opts.TicketLinks))
+	Equals(t, `JIRA-\d+`, opts.TicketLinks[0].Pattern.String())
+	Equals(t, "https://jira.example.com/browse/$0", opts.TicketLinks[0].URL)
+	Equals(t, 1, len(opts.KnownIssues))
+	Equals(t, "https://kb.example.com/state-lock", opts.KnownIssues[0].URL)
+
+	cases := []struct {
+		name    string
+		u       server.UserConfig
+		wantErr string
+	}{
+		{
+			name:    "invalid secret mask pattern",
+			u:       server.UserConfig{MarkdownSecretMaskPatterns: `["("]`},
+			wantErr: "parsing --markdown-secret-mask-patterns",
+		},
+		{
+			name:    "unknown command in success messages",
+			u:       server.UserConfig{MarkdownSuccessMessages: `{"invalid": "Done."}`},
+			wantErr: "unknown command name: invalid",
+		},
+		{
+			name:    "comment budget over 1",
+			u:       server.UserConfig{MarkdownCommentBudget: `{"diff": 0.8, "log": 0.4}`},
+			wantErr: "invalid --markdown-comment-budget",
+		},
+		{
+			name:    "invalid plan view",
+			u:       server.UserConfig{MarkdownPlanView: "json"},
+			wantErr: `invalid --markdown-plan-view "json"`,
+		},
+		{
+			name:    "invalid stale plan age",
+			u:       server.UserConfig{MarkdownStalePlanAge: "1 hour"},
+			wantErr: "parsing --markdown-stale-plan-age",
+		},
+		{
+			name:    "unknown time zone",
+			u:       server.UserConfig{MarkdownTimeZone: "Mars/Olympus"},
+			wantErr: "parsing --markdown-time-zone",
+		},
+		{
+			name:    "ticket link without url",
+			u:       server.UserConfig{MarkdownTicketLinks: `[{"pattern": "JIRA-\\d+"}]`},
+			wantErr: "each link must have a pattern and a url",
+		},
+		{
+			name:    "known issues not an array",
+			u:       server.UserConfig{MarkdownKnownIssues: `{"pattern": "state lock"}`},
+			wantErr: "parsing --markdown-known-issues",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := c.u.ToMarkdownRendererOptions()
+			assert.ErrorContains(t, err, c.wantErr)
+		})
+	}
+}